@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"users-api/src/auth"
+	"users-api/src/config"
+	"users-api/src/config/builder"
+	"users-api/src/dto"
+	"users-api/src/models"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+const adminUsage = `Uso: users-api admin [-tenant organización] <comando> [flags] [argumentos]
+
+Los comandos de usuarios operan sobre la organización de -tenant (por defecto default).
+
+Comandos:
+  create-user     -name -lastname -birthdate AAAA-MM-DD -email -password [-role] [-avatar]
+  set-role        <id|email> <rol>
+  reset-password  [-password nueva] <id|email>   (si no se indica, se genera y se imprime)
+  set-status      -reason motivo <id|email> <pending|active|suspended|disabled>
+  disable         [-reason motivo] <id|email>   (equivale a set-status ... disabled)
+  list
+  export          [-format json|csv] [-output archivo]
+  create-org      -id -name   (imprime la API key de la organización)
+  list-orgs
+  rotate-org-key  <id>        (imprime la nueva API key)`
+
+type adminCommand func(ctx context.Context, users services.UserService, args []string) error
+
+var adminCommands = map[string]adminCommand{
+	"create-user":    adminCreateUser,
+	"set-role":       adminSetRole,
+	"reset-password": adminResetPassword,
+	"set-status":     adminSetStatus,
+	"disable":        adminDisable,
+	"list":           adminList,
+	"export":         adminExport,
+}
+
+type adminOrganizationCommand func(ctx context.Context, organizations services.OrganizationService, args []string) error
+
+var adminOrganizationCommands = map[string]adminOrganizationCommand{
+	"create-org":     adminCreateOrganization,
+	"list-orgs":      adminListOrganizations,
+	"rotate-org-key": adminRotateOrganizationKey,
+}
+
+// runAdmin ejecuta un comando administrativo usando los mismos servicios que la API
+func runAdmin(cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("admin", flag.ContinueOnError)
+	tenant := fs.String("tenant", models.DefaultOrganizationID, "Organización sobre la que operan los comandos de usuarios")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, adminUsage) }
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	args = fs.Args()
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	}
+	command, isUserCommand := adminCommands[args[0]]
+	organizationCommand, isOrganizationCommand := adminOrganizationCommands[args[0]]
+	if !isUserCommand && !isOrganizationCommand {
+		fmt.Fprintln(os.Stderr, adminUsage)
+		return 2
+	}
+
+	// Los logs informativos se omiten para que la salida de list y export se pueda redirigir
+	cliConfig := *cfg
+	if cliConfig.LogLevel == "debug" || cliConfig.LogLevel == "info" {
+		cliConfig.LogLevel = "warn"
+	}
+
+	app := builder.BuildCLIApp(&cliConfig)
+	defer app.Close()
+
+	// Quien ejecuta el binario ya tiene acceso a la base, los comandos no se limitan por permisos
+	ctx := auth.WithPrincipal(context.Background(), auth.System(*tenant))
+	var err error
+	if isOrganizationCommand {
+		err = organizationCommand(ctx, app.GetOrganizationService(), args[1:])
+	} else {
+		err = command(ctx, app.GetUserService(), args[1:])
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func adminCreateUser(ctx context.Context, users services.UserService, args []string) error {
+	fs := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	name := fs.String("name", "", "Nombre")
+	lastname := fs.String("lastname", "", "Apellido")
+	birthdate := fs.String("birthdate", "", "Fecha de nacimiento (AAAA-MM-DD)")
+	email := fs.String("email", "", "Email")
+	password := fs.String("password", "", "Contraseña")
+	role := fs.String("role", "", "Rol (por defecto user)")
+	avatar := fs.String("avatar", "", "URL del avatar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	parsedBirthdate, err := time.Parse("2006-01-02", *birthdate)
+	if err != nil {
+		return fmt.Errorf("fecha de nacimiento inválida %q, el formato es AAAA-MM-DD", *birthdate)
+	}
+
+	createUserDTO := &dto.CreateUserDTO{
+		Name:      *name,
+		Lastname:  *lastname,
+		Birthdate: parsedBirthdate,
+		Role:      *role,
+		Email:     *email,
+		Password:  *password,
+		Avatar:    *avatar,
+	}
+	// Mismas validaciones que aplica gin al recibir el JSON en POST /users
+	if err := binding.Validator.ValidateStruct(createUserDTO); err != nil {
+		return err
+	}
+
+	user, err := users.CreateUser(ctx, createUserDTO)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Usuario creado: %s (%s, rol %s)\n", user.ID, user.Email, user.Role)
+	return nil
+}
+
+func adminSetRole(ctx context.Context, users services.UserService, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("uso: set-role <id|email> <rol>")
+	}
+	id, err := resolveUserID(ctx, users, args[0])
+	if err != nil {
+		return err
+	}
+
+	role := strings.TrimSpace(args[1])
+	if role == "" {
+		return fmt.Errorf("el rol no puede estar vacío")
+	}
+
+	user, err := users.UpdateUser(ctx, id, &dto.UpdateUserDTO{Role: &role})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Rol actualizado: %s ahora es %s\n", user.ID, user.Role)
+	return nil
+}
+
+func adminResetPassword(ctx context.Context, users services.UserService, args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	password := fs.String("password", "", "Nueva contraseña, si se omite se genera una")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: reset-password [-password nueva] <id|email>")
+	}
+	id, err := resolveUserID(ctx, users, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	newPassword := *password
+	generated := newPassword == ""
+	if generated {
+		if newPassword, err = generatePassword(); err != nil {
+			return err
+		}
+	}
+
+	if err := users.ResetPassword(ctx, id, newPassword); err != nil {
+		return err
+	}
+
+	if generated {
+		fmt.Printf("Contraseña reseteada para %s. Nueva contraseña: %s\n", id, newPassword)
+	} else {
+		fmt.Printf("Contraseña reseteada para %s\n", id)
+	}
+	return nil
+}
+
+func adminSetStatus(ctx context.Context, users services.UserService, args []string) error {
+	fs := flag.NewFlagSet("set-status", flag.ContinueOnError)
+	reason := fs.String("reason", "", "Motivo del cambio de estado")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 || strings.TrimSpace(*reason) == "" {
+		return fmt.Errorf("uso: set-status -reason motivo <id|email> <pending|active|suspended|disabled>")
+	}
+	return changeStatus(ctx, users, fs.Arg(0), fs.Arg(1), *reason)
+}
+
+// adminDisable deshabilita la cuenta sin borrarla, así conserva sus datos y no se puede volver a usar
+func adminDisable(ctx context.Context, users services.UserService, args []string) error {
+	fs := flag.NewFlagSet("disable", flag.ContinueOnError)
+	reason := fs.String("reason", "Deshabilitado desde la CLI", "Motivo")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: disable [-reason motivo] <id|email>")
+	}
+	return changeStatus(ctx, users, fs.Arg(0), models.UserStatusDisabled, *reason)
+}
+
+func changeStatus(ctx context.Context, users services.UserService, idOrEmail, status, reason string) error {
+	id, err := resolveUserID(ctx, users, idOrEmail)
+	if err != nil {
+		return err
+	}
+
+	user, err := users.ChangeStatus(ctx, id, status, reason)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Estado actualizado: %s ahora está %s\n", user.ID, user.Status)
+	return nil
+}
+
+func adminList(ctx context.Context, users services.UserService, args []string) error {
+	list, err := users.GetAllUsers(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tNOMBRE\tROL\tESTADO")
+	for _, user := range list {
+		fmt.Fprintf(w, "%s\t%s\t%s %s\t%s\t%s\n", user.ID, user.Email, user.Name, user.Lastname, user.Role, user.Status)
+	}
+	return w.Flush()
+}
+
+func adminExport(ctx context.Context, users services.UserService, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "json", "Formato de salida: json o csv")
+	output := fs.String("output", "", "Archivo de salida, por defecto la salida estándar")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato inválido %q", *format)
+	}
+
+	list, err := users.GetAllUsers(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(list)
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "name", "lastname", "birthdate", "role", "email", "avatar", "status"})
+	for _, user := range list {
+		writer.Write([]string{user.ID, user.Name, user.Lastname, user.Birthdate.Format("2006-01-02"), user.Role, user.Email, user.Avatar, user.Status})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func adminCreateOrganization(ctx context.Context, organizations services.OrganizationService, args []string) error {
+	fs := flag.NewFlagSet("create-org", flag.ContinueOnError)
+	id := fs.String("id", "", "ID de la organización, en minúsculas (por ejemplo acme)")
+	name := fs.String("name", "", "Nombre, por defecto el ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	organization, apiKey, err := organizations.CreateOrganization(ctx, *id, *name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Organización creada: %s (%s)\nAPI key: %s\n", organization.ID, organization.Name, apiKey)
+	return nil
+}
+
+func adminListOrganizations(ctx context.Context, organizations services.OrganizationService, args []string) error {
+	list, err := organizations.ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNOMBRE\tAPI KEY\tCREADA")
+	for _, organization := range list {
+		apiKey := "USERS_API_KEY"
+		if organization.HasAPIKey {
+			apiKey = "propia"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", organization.ID, organization.Name, apiKey, organization.CreatedAt.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+func adminRotateOrganizationKey(ctx context.Context, organizations services.OrganizationService, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("uso: rotate-org-key <id>")
+	}
+
+	apiKey, err := organizations.RotateAPIKey(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("API key rotada para %s: %s\n", args[0], apiKey)
+	return nil
+}
+
+// resolveUserID acepta un ID o un email y devuelve el ID del usuario
+func resolveUserID(ctx context.Context, users services.UserService, idOrEmail string) (string, error) {
+	if !strings.Contains(idOrEmail, "@") {
+		return idOrEmail, nil
+	}
+	user, err := users.GetUserByEmail(ctx, idOrEmail)
+	if err != nil {
+		return "", fmt.Errorf("no se encontró el usuario %s: %w", idOrEmail, err)
+	}
+	return user.ID, nil
+}
+
+// generatePassword genera contraseñas de 16 caracteres hasta obtener una con minúsculas, mayúsculas,
+// números y símbolos, así cumple cualquier PASSWORD_REQUIRE_CLASSES
+func generatePassword() (string, error) {
+	buf := make([]byte, 12)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		password := base64.RawURLEncoding.EncodeToString(buf)
+		if strings.ContainsAny(password, "abcdefghijklmnopqrstuvwxyz") && strings.ContainsAny(password, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") &&
+			strings.ContainsAny(password, "0123456789") && strings.ContainsAny(password, "-_") {
+			return password, nil
+		}
+	}
+}
@@ -1,24 +1,48 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"users-api/src/config"
 	"users-api/src/config/builder"
-	"users-api/src/config/envs"
 )
 
 func main() {
-	env := envs.LoadEnvs(".env")
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		log.Fatalf("Error al cargar la configuración: %v", err)
+	}
 
-	app := builder.BuildApp()
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			os.Exit(runMigrate(cfg, args[1:]))
+		case "admin":
+			os.Exit(runAdmin(cfg, args[1:]))
+		case "serve":
+		default:
+			log.Fatalf("Comando desconocido %q", args[0])
+		}
+	}
 
-	defer app.DisconnectDB()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	port := env.Get("PORT")
-	if port == "" {
-		port = "8080"
+	app := builder.BuildApp(cfg)
+
+	if cfg.CacheWarmUpOnStart {
+		app.WarmUpCache(cfg.CacheWarmUpLimit)
 	}
-	log.Printf("Iniciando servidor en el puerto %s...", port)
-	if err := app.GetRouter().Run(":" + port); err != nil {
+
+	if err := app.Run(ctx); err != nil {
 		log.Fatalf("Error al iniciar el servidor: %v", err)
 	}
 }
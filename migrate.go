@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"users-api/src/config"
+	"users-api/src/config/builder"
+)
+
+const migrateUsage = `Uso: users-api migrate <up|down [pasos]|status>`
+
+// runMigrate ejecuta el comando migrate y devuelve el código de salida
+func runMigrate(cfg *config.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	app := builder.NewAppBuilder(cfg).
+		BuildLogger().
+		BuildDBConnection().
+		BuildMigrator()
+	defer app.Close()
+
+	migrator := app.GetMigrator()
+	if migrator == nil {
+		fmt.Fprintln(os.Stderr, "Las migraciones solo aplican con DB_DRIVER=postgres")
+		return 2
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error al aplicar migraciones: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Migraciones aplicadas: %d\n", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed <= 0 {
+				fmt.Fprintln(os.Stderr, "La cantidad de pasos debe ser un número positivo")
+				return 2
+			}
+			steps = parsed
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error al revertir migraciones: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Migraciones revertidas: %d\n", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error al obtener el estado de las migraciones: %v\n", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNOMBRE\tESTADO\tAPLICADA")
+		for _, status := range statuses {
+			state, appliedAt := "pendiente", "-"
+			if status.Applied {
+				state, appliedAt = "aplicada", status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+		}
+		w.Flush()
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
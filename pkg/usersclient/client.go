@@ -0,0 +1,335 @@
+// Package usersclient es el cliente Go de users-api para los servicios que lo consumen
+// (courses, inscriptions). Envía la API key, reintenta con backoff los errores transitorios
+// de las operaciones idempotentes, decodifica las respuestas de error en *errors.Error y,
+// opcionalmente, cachea localmente las lecturas de usuarios.
+package usersclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"users-api/src/cache"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/errors"
+)
+
+const (
+	DefaultTimeout    = 5 * time.Second
+	DefaultMaxRetries = 2
+	DefaultBackoff    = 100 * time.Millisecond
+	maxBackoff        = 2 * time.Second
+)
+
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	cache      cache.Cache
+	cacheTTL   time.Duration
+	// cacheScope separa en la caché los usuarios de cada organización, que se identifica por la API key
+	cacheScope string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient reemplaza el http.Client usado, por ejemplo para agregar un transporte instrumentado
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout limita la duración de cada intento, no de la operación completa con sus reintentos
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries configura cuántas veces se reintenta y la espera inicial, que se duplica en cada intento
+func WithRetries(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.backoff = backoff
+	}
+}
+
+// WithCache habilita la caché local de GetUser y GetByEmail. Update y Delete hechos con este cliente
+// la invalidan, los cambios hechos por otros clientes se ven recién al vencer el ttl
+func WithCache(c cache.Cache, ttl time.Duration) Option {
+	return func(client *Client) {
+		client.cache = c
+		client.cacheTTL = ttl
+	}
+}
+
+// New crea un cliente para la API en baseURL (por ejemplo http://users-api:4001) autenticado con apiKey
+func New(baseURL, apiKey string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		cacheScope: cacheScope(apiKey),
+		httpClient: http.DefaultClient,
+		timeout:    DefaultTimeout,
+		maxRetries: DefaultMaxRetries,
+		backoff:    DefaultBackoff,
+		cache:      cache.NewNoopCache(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// cacheScope deriva de la API key un identificador que se puede usar en las claves sin exponerla
+func cacheScope(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// IsNotFound indica si err es la respuesta 404 de un usuario inexistente
+func IsNotFound(err error) bool {
+	customErr, ok := err.(*errors.Error)
+	return ok && customErr.HTTPStatusCode == http.StatusNotFound
+}
+
+func (c *Client) GetUser(ctx context.Context, id string) (*dto.UserResponseDTO, error) {
+	return c.getCached(ctx, cache.UserIDKey(c.cacheScope, id), "/v1/users/"+url.PathEscape(id))
+}
+
+func (c *Client) GetByEmail(ctx context.Context, email string) (*dto.UserResponseDTO, error) {
+	return c.getCached(ctx, cache.UserEmailKey(c.cacheScope, email), "/v1/users/email/"+url.PathEscape(email))
+}
+
+// GetUsersByIDs devuelve los usuarios existentes entre ids; los que no existen se omiten sin error
+func (c *Client) GetUsersByIDs(ctx context.Context, ids []string) ([]dto.UserResponseDTO, error) {
+	var users []dto.UserResponseDTO
+	body := map[string][]string{"ids": ids}
+	if err := c.do(ctx, http.MethodGet, "/v1/users/list", body, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (c *Client) Create(ctx context.Context, createUserDTO *dto.CreateUserDTO) (*dto.UserResponseDTO, error) {
+	var user dto.UserResponseDTO
+	if err := c.do(ctx, http.MethodPost, "/v1/users/", createUserDTO, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *Client) Update(ctx context.Context, id string, updateUserDTO *dto.UpdateUserDTO) (*dto.UserResponseDTO, error) {
+	stale, _ := c.cachedUser(ctx, cache.UserIDKey(c.cacheScope, id))
+
+	var user dto.UserResponseDTO
+	if err := c.do(ctx, http.MethodPut, "/v1/users/"+url.PathEscape(id), updateUserDTO, &user); err != nil {
+		return nil, err
+	}
+
+	c.evict(ctx, id, user.Email, stale)
+	return &user, nil
+}
+
+// ChangePassword cambia la contraseña del usuario id. current solo puede omitirse con una API key o
+// un token con permiso sobre cualquier usuario. Los tokens del usuario dejan de servir
+func (c *Client) ChangePassword(ctx context.Context, id, current, newPassword string) error {
+	stale, _ := c.cachedUser(ctx, cache.UserIDKey(c.cacheScope, id))
+
+	body := &dto.ChangePasswordDTO{CurrentPassword: current, NewPassword: newPassword}
+	if err := c.do(ctx, http.MethodPut, "/v1/users/"+url.PathEscape(id)+"/password", body, nil); err != nil {
+		return err
+	}
+
+	c.evict(ctx, id, "", stale)
+	return nil
+}
+
+func (c *Client) Delete(ctx context.Context, id string) error {
+	stale, _ := c.cachedUser(ctx, cache.UserIDKey(c.cacheScope, id))
+
+	if err := c.do(ctx, http.MethodDelete, "/v1/users/"+url.PathEscape(id), nil, nil); err != nil {
+		return err
+	}
+
+	c.evict(ctx, id, "", stale)
+	return nil
+}
+
+// Login verifica las credenciales y devuelve el usuario con su token de acceso, vacío si el servidor
+// no tiene JWT_SECRET. Con credenciales inválidas devuelve un *errors.Error con estado 401
+func (c *Client) Login(ctx context.Context, email, password string) (*dto.LoginResponseDTO, error) {
+	var login dto.LoginResponseDTO
+	body := &dto.LoginDTO{Email: email, Password: password}
+	if err := c.do(ctx, http.MethodPost, "/v1/users/login", body, &login); err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+func (c *Client) getCached(ctx context.Context, key, path string) (*dto.UserResponseDTO, error) {
+	if user, ok := c.cachedUser(ctx, key); ok {
+		return user, nil
+	}
+
+	var user dto.UserResponseDTO
+	if err := c.do(ctx, http.MethodGet, path, nil, &user); err != nil {
+		return nil, err
+	}
+
+	c.cache.Set(ctx, cache.UserIDKey(c.cacheScope, user.ID), &user, c.cacheTTL)
+	c.cache.Set(ctx, cache.UserEmailKey(c.cacheScope, user.Email), &user, c.cacheTTL)
+	return &user, nil
+}
+
+func (c *Client) cachedUser(ctx context.Context, key string) (*dto.UserResponseDTO, bool) {
+	var user *dto.UserResponseDTO
+	if err := c.cache.Get(ctx, key, &user); err != nil || user == nil {
+		return nil, false
+	}
+	return user, true
+}
+
+// evict borra las entradas del usuario, incluida la del email anterior si estaba cacheado
+func (c *Client) evict(ctx context.Context, id, email string, stale *dto.UserResponseDTO) {
+	keys := []string{cache.UserIDKey(c.cacheScope, id)}
+	if email != "" {
+		keys = append(keys, cache.UserEmailKey(c.cacheScope, email))
+	}
+	if stale != nil {
+		keys = append(keys, cache.UserEmailKey(c.cacheScope, stale.Email))
+	}
+	c.cache.Delete(ctx, keys...)
+}
+
+// do ejecuta la solicitud, reintentando solo los métodos idempotentes ante errores de red, 429 y 5xx
+func (c *Client) do(ctx context.Context, method, path string, body, dest interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	attempts := 1
+	if method != http.MethodPost {
+		attempts += c.maxRetries
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if waitErr := c.wait(ctx, attempt); waitErr != nil {
+				return err
+			}
+		}
+
+		var retry bool
+		retry, err = c.attempt(ctx, method, path, payload, dest)
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, dest interface{}) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if requestID := log.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Si se canceló el contexto del llamador no tiene sentido reintentar
+		return ctx.Err() == nil || ctx.Err() == context.DeadlineExceeded, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return retry, decodeError(resp)
+	}
+
+	if dest == nil || resp.StatusCode == http.StatusNoContent {
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
+		return false, fmt.Errorf("usersclient: respuesta inválida de %s %s: %w", method, path, err)
+	}
+	return false, nil
+}
+
+// wait espera el backoff exponencial con jitter del intento, o hasta que se cancele ctx
+func (c *Client) wait(ctx context.Context, attempt int) error {
+	delay := c.backoff << (attempt - 1)
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// defaultErrorCodes son los códigos para las respuestas de error que no traen uno propio
+var defaultErrorCodes = map[int]string{
+	http.StatusBadRequest:          errors.ErrInvalidData.Code,
+	http.StatusUnauthorized:        "UNAUTHORIZED",
+	http.StatusNotFound:            errors.ErrUserNotFound.Code,
+	http.StatusInternalServerError: errors.ErrInternalServer.Code,
+}
+
+func decodeError(resp *http.Response) *errors.Error {
+	var body struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	if body.Code == "" {
+		body.Code = defaultErrorCodes[resp.StatusCode]
+	}
+	if body.Code == "" {
+		body.Code = fmt.Sprintf("HTTP_%d", resp.StatusCode)
+	}
+	if body.Error == "" {
+		body.Error = http.StatusText(resp.StatusCode)
+	}
+	return errors.NewError(body.Code, body.Error, resp.StatusCode)
+}
@@ -0,0 +1,225 @@
+package usersclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"users-api/pkg/usersclient"
+	"users-api/src/apptest"
+	"users-api/src/cache"
+	"users-api/src/dto"
+	"users-api/src/errors"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCreateUserDTO(name string) *dto.CreateUserDTO {
+	return &dto.CreateUserDTO{
+		Name:      name,
+		Lastname:  "Test",
+		Birthdate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		Email:     name + "-" + uuid.NewString()[:8] + "@example.com",
+		Password:  "secreto123",
+	}
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	server := apptest.NewServer(t)
+	client := usersclient.New(server.URL, apptest.APIKey)
+
+	created := newCreateUserDTO("ana")
+	ana, err := client.Create(ctx, created)
+	require.NoError(t, err)
+	assert.NotEmpty(t, ana.ID)
+
+	t.Run("GetUser y GetByEmail", func(t *testing.T) {
+		user, err := client.GetUser(ctx, ana.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ana, user)
+
+		user, err = client.GetByEmail(ctx, ana.Email)
+		require.NoError(t, err)
+		assert.Equal(t, ana, user)
+	})
+
+	t.Run("GetUsersByIDs", func(t *testing.T) {
+		beto, err := client.Create(ctx, newCreateUserDTO("beto"))
+		require.NoError(t, err)
+
+		users, err := client.GetUsersByIDs(ctx, []string{ana.ID, beto.ID, uuid.NewString()})
+		require.NoError(t, err)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("Login", func(t *testing.T) {
+		login, err := client.Login(ctx, created.Email, created.Password)
+		require.NoError(t, err)
+		assert.Equal(t, ana.ID, login.ID)
+		assert.NotEmpty(t, login.AccessToken)
+		require.NotNil(t, login.ExpiresAt)
+		assert.True(t, login.ExpiresAt.After(time.Now()))
+
+		// El token sirve para llamar a la API como el usuario
+		user, err := usersclient.New(server.URL, "Bearer "+login.AccessToken).GetUser(ctx, ana.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ana.Email, user.Email)
+
+		_, err = client.Login(ctx, created.Email, "incorrecta")
+		var customErr *errors.Error
+		require.ErrorAs(t, err, &customErr)
+		assert.Equal(t, http.StatusUnauthorized, customErr.HTTPStatusCode)
+		assert.Equal(t, "INVALID CREDENTIALS", customErr.Code)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		name := "Ana María"
+		user, err := client.Update(ctx, ana.ID, &dto.UpdateUserDTO{Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, name, user.Name)
+	})
+
+	t.Run("ChangePassword", func(t *testing.T) {
+		err := client.ChangePassword(ctx, ana.ID, "incorrecta", "nueva-contraseña")
+		var customErr *errors.Error
+		require.ErrorAs(t, err, &customErr)
+		assert.Equal(t, errors.ErrWrongPassword.Code, customErr.Code)
+
+		require.NoError(t, client.ChangePassword(ctx, ana.ID, created.Password, "nueva-contraseña"))
+		_, err = client.Login(ctx, created.Email, "nueva-contraseña")
+		require.NoError(t, err)
+	})
+
+	t.Run("errores decodificados", func(t *testing.T) {
+		_, err := client.GetUser(ctx, uuid.NewString())
+		assert.True(t, usersclient.IsNotFound(err))
+
+		_, err = client.Create(ctx, &dto.CreateUserDTO{Name: "sin email"})
+		var customErr *errors.Error
+		require.ErrorAs(t, err, &customErr)
+		assert.Equal(t, errors.ErrInvalidData.Code, customErr.Code)
+		assert.Equal(t, http.StatusBadRequest, customErr.HTTPStatusCode)
+
+		_, err = usersclient.New(server.URL, "otra-clave").GetUser(ctx, ana.ID)
+		require.ErrorAs(t, err, &customErr)
+		assert.Equal(t, http.StatusUnauthorized, customErr.HTTPStatusCode)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, client.Delete(ctx, ana.ID))
+		_, err := client.GetUser(ctx, ana.ID)
+		assert.True(t, usersclient.IsNotFound(err))
+		assert.True(t, usersclient.IsNotFound(client.Delete(ctx, ana.ID)))
+	})
+}
+
+func TestClientCache(t *testing.T) {
+	ctx := context.Background()
+	server := apptest.NewServer(t)
+	client := usersclient.New(server.URL, apptest.APIKey, usersclient.WithCache(cache.NewMemoryCache(), time.Minute))
+
+	ana, err := client.Create(ctx, newCreateUserDTO("ana"))
+	require.NoError(t, err)
+
+	_, err = client.GetUser(ctx, ana.ID)
+	require.NoError(t, err)
+
+	// Un cambio hecho por fuera del cliente no se ve mientras la entrada local esté vigente
+	require.Equal(t, http.StatusOK, server.Do(t, http.MethodPut, "/v1/users/"+ana.ID, map[string]interface{}{"name": "Otra"}).StatusCode)
+	user, err := client.GetByEmail(ctx, ana.Email)
+	require.NoError(t, err)
+	assert.Equal(t, ana.Name, user.Name)
+
+	// Los cambios hechos con el cliente invalidan la caché local
+	name := "Ana María"
+	_, err = client.Update(ctx, ana.ID, &dto.UpdateUserDTO{Name: &name})
+	require.NoError(t, err)
+	user, err = client.GetUser(ctx, ana.ID)
+	require.NoError(t, err)
+	assert.Equal(t, name, user.Name)
+
+	require.NoError(t, client.Delete(ctx, ana.ID))
+	_, err = client.GetByEmail(ctx, ana.Email)
+	assert.True(t, usersclient.IsNotFound(err))
+}
+
+func TestClientRetries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reintenta los errores transitorios de las lecturas", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"id":"1","name":"ana"}`))
+		}))
+		defer server.Close()
+
+		client := usersclient.New(server.URL, "key", usersclient.WithRetries(2, time.Millisecond))
+		user, err := client.GetUser(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "ana", user.Name)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("devuelve el último error al agotar los reintentos", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"Error al eliminar el usuario"}`))
+		}))
+		defer server.Close()
+
+		client := usersclient.New(server.URL, "key", usersclient.WithRetries(2, time.Millisecond))
+		err := client.Delete(ctx, "1")
+		var customErr *errors.Error
+		require.ErrorAs(t, err, &customErr)
+		assert.Equal(t, errors.ErrInternalServer.Code, customErr.Code)
+		assert.Equal(t, "Error al eliminar el usuario", customErr.Message)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("no reintenta los POST ni los errores del cliente", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		client := usersclient.New(server.URL, "key", usersclient.WithRetries(2, time.Millisecond))
+		_, err := client.Create(ctx, newCreateUserDTO("ana"))
+		assert.Error(t, err)
+		_, err = client.GetUser(ctx, "1")
+		assert.True(t, usersclient.IsNotFound(err))
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("el timeout corta cada intento", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}))
+		defer server.Close()
+
+		client := usersclient.New(server.URL, "key", usersclient.WithTimeout(20*time.Millisecond), usersclient.WithRetries(1, time.Millisecond))
+		start := time.Now()
+		_, err := client.GetUser(ctx, "1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
@@ -0,0 +1,120 @@
+// Package apptest levanta la aplicación completa con AppBuilder sobre dependencias en memoria
+// y un httptest.Server, para tests end-to-end de la API HTTP.
+package apptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"users-api/src/config"
+	"users-api/src/config/builder"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// APIKey es la clave configurada en el servidor de pruebas
+	APIKey = "test-api-key"
+	// JWTSecret es la clave con la que el servidor de pruebas firma los tokens de acceso
+	JWTSecret = "test-jwt-secret-de-al-menos-32-bytes"
+)
+
+type Server struct {
+	*httptest.Server
+	App *builder.AppBuilder
+}
+
+// Response es una respuesta ya leída, para poder inspeccionar el cuerpo más de una vez
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Decode decodifica el cuerpo JSON en dest y falla el test si no es posible
+func (r *Response) Decode(t testing.TB, dest interface{}) {
+	t.Helper()
+	require.NoError(t, json.Unmarshal(r.Body, dest), "cuerpo: %s", r.Body)
+}
+
+// Config devuelve la configuración del servidor de pruebas: repositorio y caché en memoria, sin tracing
+func Config() *config.Config {
+	cfg := config.Default()
+	cfg.DBDriver = config.DBDriverMemory
+	cfg.CacheDriver = config.CacheDriverMemory
+	cfg.UsersAPIKey = APIKey
+	cfg.JWTSecret = JWTSecret
+	cfg.LogLevel = "error"
+	return cfg
+}
+
+// NewServer arma la aplicación con Config, aplicando opts antes de construirla, y la cierra al terminar el test
+func NewServer(t testing.TB, opts ...func(*config.Config)) *Server {
+	t.Helper()
+	return NewServerWithLogs(t, nil, opts...)
+}
+
+// NewServerWithLogs es como NewServer pero escribe los logs de la aplicación en logs
+func NewServerWithLogs(t testing.TB, logs io.Writer, opts ...func(*config.Config)) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := Config()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	require.NoError(t, cfg.Validate())
+
+	app := builder.NewAppBuilder(cfg).WithLogOutput(logs).BuildAll()
+	server := httptest.NewServer(app.GetRouter())
+	t.Cleanup(func() {
+		server.Close()
+		app.Close()
+	})
+
+	return &Server{Server: server, App: app}
+}
+
+// Do envía una solicitud autenticada con APIKey; body se codifica como JSON si no es nil
+func (s *Server) Do(t testing.TB, method, path string, body interface{}) *Response {
+	t.Helper()
+	return s.DoWithKey(t, APIKey, method, path, body)
+}
+
+// DoWithKey envía una solicitud con la clave indicada en Authorization, o sin ella si key es vacía
+func (s *Server) DoWithKey(t testing.TB, key, method, path string, body interface{}) *Response {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		if raw, ok := body.(string); ok {
+			reader = bytes.NewBufferString(raw)
+		} else {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		}
+	}
+
+	req, err := http.NewRequest(method, s.URL+path, reader)
+	require.NoError(t, err)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key != "" {
+		req.Header.Set("Authorization", key)
+	}
+
+	resp, err := s.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: data}
+}
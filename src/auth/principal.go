@@ -0,0 +1,85 @@
+// Package auth define quién hace cada solicitud, para que los servicios puedan decidir qué permitir
+// sin depender de si la llamada llegó por HTTP o gRPC.
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	// KindService es un servicio interno autenticado con la API key
+	KindService = "service"
+	// KindUser es un usuario autenticado con un token de acceso
+	KindUser = "user"
+	// KindSystem son los comandos administrativos que se ejecutan con acceso a la base
+	KindSystem = "system"
+)
+
+type Principal struct {
+	Kind string
+	// TenantID es la organización en la que opera el principal; los repositorios filtran por ella
+	TenantID string
+	UserID   string
+	Email    string
+	Role     string
+	// Groups son los grupos del usuario al emitirse su token
+	Groups []string
+	// Permissions son los permisos del rol del usuario, o los fijos de Service y System
+	Permissions []string
+}
+
+// servicePermissions son los permisos de la API key. Puede operar sobre cualquier usuario de su
+// organización pero no asignar roles, para que un registro hecho por un frontend no pueda pedir admin
+var servicePermissions = []string{
+	PermUsersReadAny, PermUsersWriteAny, PermUsersDeleteAny, PermCacheManage, PermGroupsRead, PermGroupsManage,
+}
+
+// Service es el principal de las llamadas hechas con la API key de la organización tenantID
+func Service(tenantID string) *Principal {
+	return &Principal{Kind: KindService, TenantID: tenantID, Permissions: servicePermissions}
+}
+
+// System es el principal de los comandos administrativos sobre la organización tenantID, con todos los permisos
+func System(tenantID string) *Principal {
+	return &Principal{Kind: KindSystem, TenantID: tenantID, Permissions: allPermissions()}
+}
+
+func (p *Principal) IsService() bool {
+	return p.Kind == KindService
+}
+
+// Can indica si el principal tiene el permiso. Un principal nil no tiene ninguno
+func (p *Principal) Can(permission string) bool {
+	return p != nil && slices.Contains(p.Permissions, permission)
+}
+
+// CanActOn indica si el principal puede operar sobre userID, con anyPermission sobre cualquier
+// usuario o con selfPermission si se trata de su propia cuenta
+func (p *Principal) CanActOn(userID, anyPermission, selfPermission string) bool {
+	if p.Can(anyPermission) {
+		return true
+	}
+	return p != nil && p.UserID != "" && p.UserID == userID && p.Can(selfPermission)
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext devuelve el principal de la solicitud, o nil si no se autenticó
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
+
+// TenantFromContext devuelve la organización del principal de la solicitud
+func TenantFromContext(ctx context.Context) (string, bool) {
+	principal := FromContext(ctx)
+	if principal == nil || principal.TenantID == "" {
+		return "", false
+	}
+	return principal.TenantID, true
+}
@@ -0,0 +1,70 @@
+package auth
+
+// Permisos que se asignan a los roles. Los sufijos .any y .self distinguen entre operar sobre
+// cualquier usuario o solo sobre la propia cuenta
+const (
+	PermUsersReadAny   = "users.read.any"
+	PermUsersReadSelf  = "users.read.self"
+	PermUsersWriteAny  = "users.write.any"
+	PermUsersWriteSelf = "users.write.self"
+	PermUsersDeleteAny = "users.delete.any"
+	PermUsersStatus    = "users.status.manage"
+	// PermUsersPasswordReset permite cambiar la contraseña de otro usuario sin conocer la actual. No lo
+	// tiene la API key, así un frontend no puede tomar cuentas ajenas
+	PermUsersPasswordReset = "users.password.reset"
+	PermRolesAssign        = "roles.assign"
+	PermCacheManage        = "cache.manage"
+	PermGroupsRead         = "groups.read"
+	PermGroupsManage       = "groups.manage"
+	// PermOrganizationsManage no lo tiene ningún rol, las organizaciones se crean desde la CLI
+	PermOrganizationsManage = "organizations.manage"
+)
+
+// Permissions describe cada permiso. Es la fuente de la que se siembran las tablas
+// permissions y role_permissions en SQLite y en memoria, y debe coincidir con la migración de PostgreSQL
+var Permissions = map[string]string{
+	PermUsersReadAny:        "Consultar cualquier usuario",
+	PermUsersReadSelf:       "Consultar la propia cuenta",
+	PermUsersWriteAny:       "Crear y modificar cualquier usuario",
+	PermUsersWriteSelf:      "Modificar y eliminar la propia cuenta",
+	PermUsersDeleteAny:      "Eliminar cualquier usuario",
+	PermUsersStatus:         "Activar, suspender o deshabilitar cuentas",
+	PermUsersPasswordReset:  "Cambiar la contraseña de cualquier usuario sin conocer la actual",
+	PermRolesAssign:         "Asignar o cambiar el rol de un usuario",
+	PermCacheManage:         "Administrar la caché",
+	PermGroupsRead:          "Consultar grupos y sus miembros",
+	PermGroupsManage:        "Crear, modificar y eliminar grupos y sus miembros",
+	PermOrganizationsManage: "Crear organizaciones y rotar sus API keys",
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// DefaultRole es el rol de los usuarios creados sin indicar uno
+const DefaultRole = RoleUser
+
+// DefaultRoles son los roles que existen al crear la base, con sus permisos
+var DefaultRoles = map[string][]string{
+	RoleUser: {PermUsersReadSelf, PermUsersWriteSelf},
+	RoleAdmin: {
+		PermUsersReadAny, PermUsersReadSelf, PermUsersWriteAny, PermUsersWriteSelf,
+		PermUsersDeleteAny, PermUsersStatus, PermUsersPasswordReset, PermRolesAssign, PermCacheManage, PermGroupsRead, PermGroupsManage,
+	},
+}
+
+// RoleDescriptions describe los roles de DefaultRoles
+var RoleDescriptions = map[string]string{
+	RoleUser:  "Usuario final, solo opera sobre su cuenta",
+	RoleAdmin: "Administrador con todos los permisos",
+}
+
+// allPermissions devuelve todos los permisos definidos
+func allPermissions() []string {
+	permissions := make([]string, 0, len(Permissions))
+	for permission := range Permissions {
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTTL es el tiempo de vida por defecto de las entradas de caché
+const DefaultTTL = 5 * time.Minute
+
+// ErrCacheMiss se devuelve cuando la clave no existe en la caché
+var ErrCacheMiss = errors.New("cache: clave no encontrada")
+
+// Keyspace agrupa las claves de un mismo tipo bajo un patrón
+type Keyspace struct {
+	Name    string
+	Pattern string
+	// TenantPattern es el formato del patrón limitado a una organización. Los grupos sin él, como los
+	// roles y las API keys, son compartidos y no se vacían ni cuentan desde la API de una organización
+	TenantPattern string
+}
+
+// Keyspaces son los grupos de claves que administra este servicio
+var Keyspaces = []Keyspace{
+	{Name: "user_id", Pattern: "user_id:*", TenantPattern: "user_id:%s:*"},
+	{Name: "user_email", Pattern: "user_email:*", TenantPattern: "user_email:%s:*"},
+	{Name: "auth_email", Pattern: "auth_email:*", TenantPattern: "auth_email:%s:*"},
+	{Name: "all_users", Pattern: "all_users:*", TenantPattern: "all_users:%s"},
+	{Name: "role", Pattern: "role:*"},
+	{Name: "org_key", Pattern: "org_key:*"},
+}
+
+// TenantKeyspaces devuelve los grupos de claves de la organización tenantID, con Pattern limitado a ella
+func TenantKeyspaces(tenantID string) []Keyspace {
+	keyspaces := make([]Keyspace, 0, len(Keyspaces))
+	for _, keyspace := range Keyspaces {
+		if keyspace.TenantPattern == "" {
+			continue
+		}
+		keyspace.Pattern = fmt.Sprintf(keyspace.TenantPattern, escapePattern(tenantID))
+		keyspaces = append(keyspaces, keyspace)
+	}
+	return keyspaces
+}
+
+// escapePattern escapa los comodines de glob, que SCAN y path.Match interpretan igual
+func escapePattern(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Las claves de usuarios incluyen la organización, porque el mismo email o ID puede consultarse
+// desde varias. AllUsersKey es la clave donde se guarda el listado completo de una organización
+func AllUsersKey(tenantID string) string {
+	return fmt.Sprintf("all_users:%s", tenantID)
+}
+
+func UserIDKey(tenantID, id string) string {
+	return fmt.Sprintf("user_id:%s:%s", tenantID, id)
+}
+
+func UserEmailKey(tenantID, email string) string {
+	return fmt.Sprintf("user_email:%s:%s", tenantID, email)
+}
+
+// AuthEmailKey guarda el usuario con su hash de contraseña para el login,
+// separado de UserEmailKey que solo guarda la respuesta pública
+func AuthEmailKey(tenantID, email string) string {
+	return fmt.Sprintf("auth_email:%s:%s", tenantID, email)
+}
+
+// RoleKey guarda los permisos de un rol, que se consultan en cada solicitud con token
+func RoleKey(name string) string {
+	return fmt.Sprintf("role:%s", name)
+}
+
+// OrganizationKeyKey guarda la organización que corresponde al hash de una API key
+func OrganizationKeyKey(apiKeyHash string) string {
+	return fmt.Sprintf("org_key:%s", apiKeyHash)
+}
+
+type Stats struct {
+	Enabled bool             `json:"enabled"`
+	Hits    uint64           `json:"hits"`
+	Misses  uint64           `json:"misses"`
+	Keys    map[string]int64 `json:"keys"`
+}
+
+type Cache interface {
+	Enabled() bool
+	Get(ctx context.Context, key string, dest interface{}) error
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// Flush elimina las claves de la organización tenantID y devuelve cuántas borró
+	Flush(ctx context.Context, tenantID string) (int64, error)
+	// Stats cuenta las claves de la organización tenantID. Los aciertos y fallos son del proceso
+	Stats(ctx context.Context, tenantID string) (*Stats, error)
+}
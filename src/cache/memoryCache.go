@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryCache es una caché en proceso para tests y desarrollo local, con la misma
+// serialización JSON y el mismo manejo de TTL que la de Redis
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func NewMemoryCache() Cache {
+	return &memoryCache{entries: map[string]memoryEntry{}}
+}
+
+func (c *memoryCache) Enabled() bool {
+	return true
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		c.misses.Add(1)
+		return ErrCacheMiss
+	}
+	if err := json.Unmarshal(entry.value, dest); err != nil {
+		c.misses.Add(1)
+		return err
+	}
+	c.hits.Add(1)
+	return nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	entry := memoryEntry{value: data}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *memoryCache) Flush(ctx context.Context, tenantID string) (int64, error) {
+	keyspaces := TenantKeyspaces(tenantID)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var deleted int64
+	for key := range c.entries {
+		if keyspaceOf(keyspaces, key) != "" {
+			delete(c.entries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (c *memoryCache) Stats(ctx context.Context, tenantID string) (*Stats, error) {
+	keyspaces := TenantKeyspaces(tenantID)
+	stats := &Stats{
+		Enabled: true,
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Keys:    make(map[string]int64, len(keyspaces)),
+	}
+	for _, keyspace := range keyspaces {
+		stats.Keys[keyspace.Name] = 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, entry := range c.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			continue
+		}
+		if name := keyspaceOf(keyspaces, key); name != "" {
+			stats.Keys[name]++
+		}
+	}
+	return stats, nil
+}
+
+// keyspaceOf devuelve el nombre del grupo de keyspaces al que pertenece key, con el mismo glob que SCAN
+func keyspaceOf(keyspaces []Keyspace, key string) string {
+	for _, keyspace := range keyspaces {
+		if matched, _ := path.Match(keyspace.Pattern, key); matched {
+			return keyspace.Name
+		}
+	}
+	return ""
+}
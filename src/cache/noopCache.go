@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// noopCache se usa cuando Redis no está disponible, toda lectura es un miss
+type noopCache struct{}
+
+func NewNoopCache() Cache {
+	return noopCache{}
+}
+
+func (noopCache) Enabled() bool {
+	return false
+}
+
+func (noopCache) Get(ctx context.Context, key string, dest interface{}) error {
+	return ErrCacheMiss
+}
+
+func (noopCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return nil
+}
+
+func (noopCache) Delete(ctx context.Context, keys ...string) error {
+	return nil
+}
+
+func (noopCache) Flush(ctx context.Context, tenantID string) (int64, error) {
+	return 0, nil
+}
+
+func (noopCache) Stats(ctx context.Context, tenantID string) (*Stats, error) {
+	return &Stats{Enabled: false, Keys: map[string]int64{}}, nil
+}
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("users-api/src/cache")
+
+func startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "redis "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationName(operation)))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil && err != ErrCacheMiss {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type redisCache struct {
+	client *redis.Client
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewRedisCache(client *redis.Client) Cache {
+	return &redisCache{client: client}
+}
+
+func (c *redisCache) Enabled() bool {
+	return true
+}
+
+func (c *redisCache) Get(ctx context.Context, key string, dest interface{}) (err error) {
+	ctx, span := startSpan(ctx, "GET")
+	defer func() {
+		span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+		endSpan(span, err)
+	}()
+
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		c.misses.Add(1)
+		return ErrCacheMiss
+	}
+	if err != nil {
+		c.misses.Add(1)
+		return err
+	}
+
+	if err := json.Unmarshal(value, dest); err != nil {
+		c.misses.Add(1)
+		return err
+	}
+
+	c.hits.Add(1)
+	return nil
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) (err error) {
+	ctx, span := startSpan(ctx, "SET")
+	defer func() { endSpan(span, err) }()
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, key, data, ttl).Err()
+}
+
+func (c *redisCache) Delete(ctx context.Context, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx, span := startSpan(ctx, "DEL")
+	defer func() { endSpan(span, err) }()
+
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// Flush elimina solo las claves de este servicio, la instancia de Redis puede ser compartida
+func (c *redisCache) Flush(ctx context.Context, tenantID string) (deleted int64, err error) {
+	ctx, span := startSpan(ctx, "FLUSH")
+	defer func() { endSpan(span, err) }()
+
+	for _, keyspace := range TenantKeyspaces(tenantID) {
+		keys, err := c.scan(ctx, keyspace.Pattern)
+		if err != nil {
+			return deleted, err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		n, err := c.client.Del(ctx, keys...).Result()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
+
+func (c *redisCache) Stats(ctx context.Context, tenantID string) (_ *Stats, err error) {
+	ctx, span := startSpan(ctx, "STATS")
+	defer func() { endSpan(span, err) }()
+
+	stats := &Stats{
+		Enabled: true,
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Keys:    map[string]int64{},
+	}
+
+	for _, keyspace := range TenantKeyspaces(tenantID) {
+		keys, err := c.scan(ctx, keyspace.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		stats.Keys[keyspace.Name] = int64(len(keys))
+	}
+
+	return stats, nil
+}
+
+func (c *redisCache) scan(ctx context.Context, pattern string) ([]string, error) {
+	var keys []string
+	iter := c.client.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,310 @@
+// Package clienttest contiene la suite de contrato que toda implementación de
+// client.UserRepository (Postgres, SQLite, memoria) debe pasar.
+package clienttest
+
+import (
+	"context"
+	"testing"
+	"time"
+	"users-api/src/auth"
+	"users-api/src/client"
+	"users-api/src/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// OtherTenant es la segunda organización con la que se prueba el aislamiento. En PostgreSQL
+// tiene que existir en organizations antes de correr la suite
+const OtherTenant = "clienttest"
+
+// TenantContext devuelve un contexto con un principal de la organización tenantID
+func TenantContext(tenantID string) context.Context {
+	return auth.WithPrincipal(context.Background(), auth.System(tenantID))
+}
+
+// NewUser devuelve un usuario válido con ID y email únicos
+func NewUser(name string) *models.User {
+	id := uuid.New().String()
+	return &models.User{
+		ID:        id,
+		Name:      name,
+		Lastname:  "Test",
+		Birthdate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		Role:      "user",
+		Email:     name + "-" + id[:8] + "@example.com",
+		Password:  "hash",
+		Avatar:    "https://example.com/avatar.png",
+	}
+}
+
+// RunUserRepositoryContract ejecuta la suite; newRepo debe devolver un repositorio vacío en cada llamada
+func RunUserRepositoryContract(t *testing.T, newRepo func(t *testing.T) client.UserRepository) {
+	ctx := TenantContext(models.DefaultOrganizationID)
+	otherCtx := TenantContext(OtherTenant)
+
+	t.Run("Create y ReadOne devuelven el mismo usuario", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		found, err := repo.ReadOne(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+		assert.Equal(t, user.Name, found.Name)
+		assert.Equal(t, user.Lastname, found.Lastname)
+		assert.True(t, user.Birthdate.Equal(found.Birthdate))
+		assert.Equal(t, user.Role, found.Role)
+		assert.Equal(t, user.Email, found.Email)
+		assert.Equal(t, user.Password, found.Password)
+		assert.Equal(t, user.Avatar, found.Avatar)
+		assert.Equal(t, models.DefaultOrganizationID, found.TenantID)
+		assert.False(t, found.CreatedAt.IsZero())
+		assert.False(t, found.UpdatedAt.IsZero())
+	})
+
+	t.Run("Create rechaza emails duplicados", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		duplicate := NewUser("otra")
+		duplicate.Email = user.Email
+		assert.Error(t, repo.Create(ctx, duplicate))
+	})
+
+	t.Run("ReadOne y ReadByEmail devuelven ErrRecordNotFound si no existe", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.ReadOne(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = repo.ReadByEmail(ctx, "nadie@example.com")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("ReadByEmail encuentra al usuario", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		found, err := repo.ReadByEmail(ctx, user.Email)
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+	})
+
+	t.Run("ReadAll devuelve todos los usuarios", func(t *testing.T) {
+		repo := newRepo(t)
+		users, err := repo.ReadAll(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+
+		for _, name := range []string{"ana", "beto", "carla"} {
+			require.NoError(t, repo.Create(ctx, NewUser(name)))
+		}
+
+		users, err = repo.ReadAll(ctx)
+		require.NoError(t, err)
+		assert.Len(t, users, 3)
+	})
+
+	t.Run("GetUsersList devuelve solo los IDs pedidos que existen", func(t *testing.T) {
+		repo := newRepo(t)
+		ana, beto, carla := NewUser("ana"), NewUser("beto"), NewUser("carla")
+		for _, user := range []*models.User{ana, beto, carla} {
+			require.NoError(t, repo.Create(ctx, user))
+		}
+
+		users, err := repo.GetUsersList(ctx, []string{ana.ID, carla.ID, uuid.New().String()})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{ana.ID, carla.ID}, ids(users))
+
+		users, err = repo.GetUsersList(ctx, []string{uuid.New().String()})
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("Update solo modifica los campos informados", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.Update(ctx, user.ID, &models.User{Name: "Ana María", Role: "admin"}))
+
+		found, err := repo.ReadOne(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Ana María", found.Name)
+		assert.Equal(t, "admin", found.Role)
+		assert.Equal(t, user.Lastname, found.Lastname)
+		assert.Equal(t, user.Email, found.Email)
+		assert.Equal(t, user.Password, found.Password)
+	})
+
+	t.Run("UpdateStatus solo cambia el estado si sigue siendo el esperado", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		found, err := repo.ReadOne(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusActive, found.Status)
+
+		at := time.Now().UTC().Truncate(time.Second)
+		require.NoError(t, repo.UpdateStatus(ctx, user.ID, models.UserStatusActive, models.UserStatusSuspended, "pagos atrasados", at))
+		assert.ErrorIs(t, repo.UpdateStatus(ctx, user.ID, models.UserStatusActive, models.UserStatusDisabled, "otro", at), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.UpdateStatus(otherCtx, user.ID, models.UserStatusSuspended, models.UserStatusActive, "otro", at), gorm.ErrRecordNotFound)
+
+		found, err = repo.ReadOne(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.UserStatusSuspended, found.Status)
+		assert.Equal(t, "pagos atrasados", found.StatusReason)
+		require.NotNil(t, found.StatusChangedAt)
+		assert.True(t, at.Equal(*found.StatusChangedAt))
+		assert.Equal(t, user.Name, found.Name)
+	})
+
+	t.Run("UpdatePassword solo reemplaza el hash si no cambió", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		require.NoError(t, repo.UpdatePassword(ctx, user.ID, user.Password, "nuevo-hash"))
+		assert.ErrorIs(t, repo.UpdatePassword(ctx, user.ID, user.Password, "otro-hash"), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.UpdatePassword(otherCtx, user.ID, "nuevo-hash", "otro-hash"), gorm.ErrRecordNotFound)
+
+		found, err := repo.ReadOne(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "nuevo-hash", found.Password)
+		assert.Equal(t, user.Name, found.Name)
+	})
+
+	t.Run("ChangePassword guarda el historial y conserva las más recientes", func(t *testing.T) {
+		repo := newRepo(t)
+		user := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, user))
+
+		hashes := []string{user.Password, "hash-1", "hash-2", "hash-3"}
+		start := time.Now().UTC().Truncate(time.Second)
+		for i := 1; i < len(hashes); i++ {
+			require.NoError(t, repo.ChangePassword(ctx, user.ID, hashes[i-1], hashes[i], start.Add(time.Duration(i)*time.Second), 2))
+		}
+		assert.ErrorIs(t, repo.ChangePassword(ctx, user.ID, "hash-1", "hash-4", start, 2), gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, repo.ChangePassword(otherCtx, user.ID, "hash-3", "hash-4", start, 2), gorm.ErrRecordNotFound)
+
+		found, err := repo.ReadOne(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "hash-3", found.Password)
+		require.NotNil(t, found.PasswordChangedAt)
+		assert.True(t, start.Add(3*time.Second).Equal(*found.PasswordChangedAt))
+
+		history, err := repo.ReadPasswordHistory(ctx, user.ID, 5)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hash-2", "hash-1"}, history)
+		history, err = repo.ReadPasswordHistory(ctx, user.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hash-2"}, history)
+		history, err = repo.ReadPasswordHistory(otherCtx, user.ID, 5)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+
+		// keep en 0 deshabilita el historial y borra el anterior
+		require.NoError(t, repo.ChangePassword(ctx, user.ID, "hash-3", "hash-4", start.Add(4*time.Second), 0))
+		history, err = repo.ReadPasswordHistory(ctx, user.ID, 5)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+	})
+
+	t.Run("Delete es lógico y oculta al usuario de todas las lecturas", func(t *testing.T) {
+		repo := newRepo(t)
+		ana, beto := NewUser("ana"), NewUser("beto")
+		require.NoError(t, repo.Create(ctx, ana))
+		require.NoError(t, repo.Create(ctx, beto))
+
+		require.NoError(t, repo.Delete(ctx, ana.ID))
+
+		_, err := repo.ReadOne(ctx, ana.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.ReadByEmail(ctx, ana.Email)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		users, err := repo.ReadAll(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{beto.ID}, ids(users))
+
+		users, err = repo.GetUsersList(ctx, []string{ana.ID, beto.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{beto.ID}, ids(users))
+	})
+
+	t.Run("sin organización no consulta", func(t *testing.T) {
+		repo := newRepo(t)
+
+		assert.ErrorIs(t, repo.Create(context.Background(), NewUser("ana")), client.ErrNoTenant)
+		_, err := repo.ReadAll(context.Background())
+		assert.ErrorIs(t, err, client.ErrNoTenant)
+		_, err = repo.ReadOne(context.Background(), uuid.New().String())
+		assert.ErrorIs(t, err, client.ErrNoTenant)
+	})
+
+	t.Run("las organizaciones están aisladas", func(t *testing.T) {
+		repo := newRepo(t)
+		ana := NewUser("ana")
+		require.NoError(t, repo.Create(ctx, ana))
+
+		// El mismo email puede existir en otra organización
+		otherAna := NewUser("ana")
+		otherAna.Email = ana.Email
+		require.NoError(t, repo.Create(otherCtx, otherAna))
+		assert.Equal(t, OtherTenant, otherAna.TenantID)
+
+		found, err := repo.ReadByEmail(otherCtx, ana.Email)
+		require.NoError(t, err)
+		assert.Equal(t, otherAna.ID, found.ID)
+
+		_, err = repo.ReadOne(otherCtx, ana.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		users, err := repo.ReadAll(otherCtx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{otherAna.ID}, ids(users))
+
+		users, err = repo.GetUsersList(otherCtx, []string{ana.ID, otherAna.ID})
+		require.NoError(t, err)
+		assert.Equal(t, []string{otherAna.ID}, ids(users))
+
+		users, err = repo.ReadRecent(otherCtx, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{otherAna.ID}, ids(users))
+
+		// Update y Delete desde otra organización no afectan al usuario
+		require.NoError(t, repo.Update(otherCtx, ana.ID, &models.User{Name: "Intrusa"}))
+		require.NoError(t, repo.Delete(otherCtx, ana.ID))
+		found, err = repo.ReadOne(ctx, ana.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ana.Name, found.Name)
+	})
+
+	t.Run("ReadRecent ordena por última actualización y respeta el límite", func(t *testing.T) {
+		repo := newRepo(t)
+		ana, beto, carla := NewUser("ana"), NewUser("beto"), NewUser("carla")
+		for _, user := range []*models.User{ana, beto, carla} {
+			require.NoError(t, repo.Create(ctx, user))
+			time.Sleep(5 * time.Millisecond)
+		}
+		require.NoError(t, repo.Update(ctx, ana.ID, &models.User{Avatar: "https://example.com/nuevo.png"}))
+
+		users, err := repo.ReadRecent(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ana.ID, carla.ID}, ids(users))
+	})
+}
+
+func ids(users []models.User) []string {
+	result := make([]string, 0, len(users))
+	for _, user := range users {
+		result = append(result, user.ID)
+	}
+	return result
+}
@@ -0,0 +1,190 @@
+package clienttest
+
+import (
+	"context"
+	"testing"
+	"users-api/src/client"
+	"users-api/src/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// GroupRepositories son los repositorios que usa la suite de grupos, sobre la misma base
+type GroupRepositories struct {
+	Users       client.UserRepository
+	Groups      client.GroupRepository
+	Memberships client.MembershipRepository
+}
+
+// NewGroup devuelve un grupo válido con ID y nombre únicos
+func NewGroup(name string) *models.Group {
+	id := uuid.New().String()
+	return &models.Group{ID: id, Name: name + "-" + id[:8], Description: "Grupo " + name}
+}
+
+// RunGroupRepositoryContract ejecuta la suite de client.GroupRepository y client.MembershipRepository;
+// newRepos debe devolver repositorios vacíos en cada llamada
+func RunGroupRepositoryContract(t *testing.T, newRepos func(t *testing.T) GroupRepositories) {
+	ctx := TenantContext(models.DefaultOrganizationID)
+	otherCtx := TenantContext(OtherTenant)
+
+	createUsers := func(t *testing.T, repos GroupRepositories, n int) []string {
+		t.Helper()
+		ids := make([]string, 0, n)
+		for i := 0; i < n; i++ {
+			user := NewUser("miembro")
+			require.NoError(t, repos.Users.Create(ctx, user))
+			ids = append(ids, user.ID)
+		}
+		return ids
+	}
+
+	t.Run("Create, ReadOne y ReadByName devuelven el mismo grupo", func(t *testing.T) {
+		repos := newRepos(t)
+		group := NewGroup("cohorte")
+		require.NoError(t, repos.Groups.Create(ctx, group))
+		assert.Equal(t, models.DefaultOrganizationID, group.TenantID)
+
+		found, err := repos.Groups.ReadOne(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, group.Name, found.Name)
+		assert.Equal(t, group.Description, found.Description)
+
+		found, err = repos.Groups.ReadByName(ctx, group.Name)
+		require.NoError(t, err)
+		assert.Equal(t, group.ID, found.ID)
+
+		_, err = repos.Groups.ReadOne(ctx, uuid.New().String())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("ReadPage ordena por nombre, pagina y cuenta el total", func(t *testing.T) {
+		repos := newRepos(t)
+		for _, name := range []string{"c", "a", "b"} {
+			require.NoError(t, repos.Groups.Create(ctx, &models.Group{ID: uuid.New().String(), Name: name}))
+		}
+
+		groups, total, err := repos.Groups.ReadPage(ctx, 2, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "b", groups[0].Name)
+		assert.Equal(t, "c", groups[1].Name)
+	})
+
+	t.Run("Update permite vaciar la descripción", func(t *testing.T) {
+		repos := newRepos(t)
+		group := NewGroup("cohorte")
+		require.NoError(t, repos.Groups.Create(ctx, group))
+
+		require.NoError(t, repos.Groups.Update(ctx, group.ID, &models.Group{Name: "renombrado"}))
+		found, err := repos.Groups.ReadOne(ctx, group.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "renombrado", found.Name)
+		assert.Empty(t, found.Description)
+	})
+
+	t.Run("AddMembers ignora duplicados y RemoveMembers cuenta los quitados", func(t *testing.T) {
+		repos := newRepos(t)
+		group := NewGroup("cohorte")
+		require.NoError(t, repos.Groups.Create(ctx, group))
+		ids := createUsers(t, repos, 3)
+
+		added, err := repos.Memberships.AddMembers(ctx, group.ID, ids[:2])
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), added)
+		added, err = repos.Memberships.AddMembers(ctx, group.ID, ids)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), added)
+
+		removed, err := repos.Memberships.RemoveMembers(ctx, group.ID, []string{ids[0], uuid.New().String()})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), removed)
+
+		members, total, err := repos.Memberships.ReadMemberIDs(ctx, group.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.ElementsMatch(t, ids[1:], members)
+
+		groupIDs, err := repos.Memberships.ReadGroupIDs(ctx, ids[1])
+		require.NoError(t, err)
+		assert.Equal(t, []string{group.ID}, groupIDs)
+	})
+
+	t.Run("ReadMemberIDs pagina y omite usuarios eliminados", func(t *testing.T) {
+		repos := newRepos(t)
+		group := NewGroup("cohorte")
+		require.NoError(t, repos.Groups.Create(ctx, group))
+		ids := createUsers(t, repos, 4)
+		_, err := repos.Memberships.AddMembers(ctx, group.ID, ids)
+		require.NoError(t, err)
+		require.NoError(t, repos.Users.Delete(ctx, ids[0]))
+
+		first, total, err := repos.Memberships.ReadMemberIDs(ctx, group.ID, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, first, 2)
+		rest, _, err := repos.Memberships.ReadMemberIDs(ctx, group.ID, 2, 2)
+		require.NoError(t, err)
+		require.Len(t, rest, 1)
+		assert.ElementsMatch(t, ids[1:], append(first, rest...))
+	})
+
+	t.Run("Delete elimina el grupo y sus miembros", func(t *testing.T) {
+		repos := newRepos(t)
+		group := NewGroup("cohorte")
+		require.NoError(t, repos.Groups.Create(ctx, group))
+		ids := createUsers(t, repos, 1)
+		_, err := repos.Memberships.AddMembers(ctx, group.ID, ids)
+		require.NoError(t, err)
+
+		require.NoError(t, repos.Groups.Delete(ctx, group.ID))
+		_, err = repos.Groups.ReadOne(ctx, group.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		groupIDs, err := repos.Memberships.ReadGroupIDs(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Empty(t, groupIDs)
+	})
+
+	t.Run("las organizaciones están aisladas", func(t *testing.T) {
+		repos := newRepos(t)
+		group := NewGroup("cohorte")
+		require.NoError(t, repos.Groups.Create(ctx, group))
+		ids := createUsers(t, repos, 1)
+		_, err := repos.Memberships.AddMembers(ctx, group.ID, ids)
+		require.NoError(t, err)
+
+		// El mismo nombre se puede usar en otra organización
+		require.NoError(t, repos.Groups.Create(otherCtx, &models.Group{ID: uuid.New().String(), Name: group.Name}))
+
+		_, err = repos.Groups.ReadOne(otherCtx, group.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		groups, total, err := repos.Groups.ReadPage(otherCtx, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.NotEqual(t, group.ID, groups[0].ID)
+
+		members, total, err := repos.Memberships.ReadMemberIDs(otherCtx, group.ID, 10, 0)
+		require.NoError(t, err)
+		assert.Zero(t, total)
+		assert.Empty(t, members)
+		removed, err := repos.Memberships.RemoveMembers(otherCtx, group.ID, ids)
+		require.NoError(t, err)
+		assert.Zero(t, removed)
+
+		require.NoError(t, repos.Groups.Delete(otherCtx, group.ID))
+		_, err = repos.Groups.ReadOne(ctx, group.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("sin organización no consulta", func(t *testing.T) {
+		repos := newRepos(t)
+		_, _, err := repos.Groups.ReadPage(context.Background(), 10, 0)
+		assert.ErrorIs(t, err, client.ErrNoTenant)
+		_, err = repos.Memberships.ReadGroupIDs(context.Background(), uuid.New().String())
+		assert.ErrorIs(t, err, client.ErrNoTenant)
+	})
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"context"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GroupRepository opera sobre los grupos de la organización del principal de ctx, como UserRepository
+type GroupRepository interface {
+	Create(ctx context.Context, group *models.Group) error
+	// ReadPage devuelve los grupos ordenados por nombre desde offset, y el total de la organización
+	ReadPage(ctx context.Context, limit, offset int) ([]models.Group, int64, error)
+	ReadList(ctx context.Context, ids []string) ([]models.Group, error)
+	ReadOne(ctx context.Context, id string) (*models.Group, error)
+	ReadByName(ctx context.Context, name string) (*models.Group, error)
+	Update(ctx context.Context, id string, group *models.Group) error
+	// Delete elimina el grupo junto con sus miembros
+	Delete(ctx context.Context, id string) error
+}
+
+// MembershipRepository administra qué usuarios pertenecen a cada grupo. Los usuarios eliminados
+// no se cuentan como miembros aunque su pertenencia siga guardada
+type MembershipRepository interface {
+	// AddMembers agrega los usuarios al grupo, ignorando los que ya eran miembros, y devuelve cuántos agregó
+	AddMembers(ctx context.Context, groupID string, userIDs []string) (int64, error)
+	// RemoveMembers quita los usuarios del grupo y devuelve cuántos eran miembros
+	RemoveMembers(ctx context.Context, groupID string, userIDs []string) (int64, error)
+	// ReadMemberIDs devuelve los miembros en orden de ingreso desde offset, y el total del grupo
+	ReadMemberIDs(ctx context.Context, groupID string, limit, offset int) ([]string, int64, error)
+	ReadGroupIDs(ctx context.Context, userID string) ([]string, error)
+}
+
+type groupRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewGroupRepository(db *gorm.DB, logger *zap.Logger) GroupRepository {
+	return &groupRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *groupRepository) Create(ctx context.Context, group *models.Group) error {
+	ctx, span := startTableSpan(ctx, "GroupRepository.Create", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	group.TenantID = tenantID
+	if err := r.db.WithContext(ctx).Create(group).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al crear grupo en BD",
+			zap.String("name", group.Name), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+func (r *groupRepository) ReadPage(ctx context.Context, limit, offset int) ([]models.Group, int64, error) {
+	ctx, span := startTableSpan(ctx, "GroupRepository.ReadPage", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.Group{}).Where("tenant_id = ?", tenantID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al contar grupos en BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+
+	var groups []models.Group
+	if err := query.Order("name").Limit(limit).Offset(offset).Find(&groups).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener grupos de BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *groupRepository) ReadList(ctx context.Context, ids []string) ([]models.Group, error) {
+	ctx, span := startTableSpan(ctx, "GroupRepository.ReadList", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var groups []models.Group
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id IN ?", tenantID, ids).Order("name").Find(&groups).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener lista de grupos de BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *groupRepository) ReadOne(ctx context.Context, id string) (*models.Group, error) {
+	ctx, span := startTableSpan(ctx, "GroupRepository.ReadOne", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var group models.Group
+	if err := r.db.WithContext(ctx).First(&group, "tenant_id = ? AND id = ?", tenantID, id).Error; err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) ReadByName(ctx context.Context, name string) (*models.Group, error) {
+	ctx, span := startTableSpan(ctx, "GroupRepository.ReadByName", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var group models.Group
+	if err := r.db.WithContext(ctx).First(&group, "tenant_id = ? AND name = ?", tenantID, name).Error; err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) Update(ctx context.Context, id string, group *models.Group) error {
+	ctx, span := startTableSpan(ctx, "GroupRepository.Update", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	// Select incluye la descripción para poder vaciarla, que Updates omitiría por ser el valor cero
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).
+		Select("name", "description", "updated_at").Updates(group).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al actualizar grupo en BD",
+			zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := startTableSpan(ctx, "GroupRepository.Delete", "groups")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	// En PostgreSQL la FK borra los miembros en cascada, SQLite no aplica FKs por defecto
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.GroupMember{}, "tenant_id = ? AND group_id = ?", tenantID, id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Group{}, "tenant_id = ? AND id = ?", tenantID, id).Error
+	})
+	if err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al eliminar grupo en BD",
+			zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+type membershipRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewMembershipRepository(db *gorm.DB, logger *zap.Logger) MembershipRepository {
+	return &membershipRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *membershipRepository) AddMembers(ctx context.Context, groupID string, userIDs []string) (int64, error) {
+	ctx, span := startTableSpan(ctx, "MembershipRepository.AddMembers", "group_members")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+
+	members := make([]models.GroupMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, models.GroupMember{GroupID: groupID, UserID: userID, TenantID: tenantID})
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&members)
+	if result.Error != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al agregar miembros en BD",
+			zap.String("group_id", groupID), zap.Error(result.Error))
+		tracing.RecordError(span, result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *membershipRepository) RemoveMembers(ctx context.Context, groupID string, userIDs []string) (int64, error) {
+	ctx, span := startTableSpan(ctx, "MembershipRepository.RemoveMembers", "group_members")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+
+	result := r.db.WithContext(ctx).Delete(&models.GroupMember{},
+		"tenant_id = ? AND group_id = ? AND user_id IN ?", tenantID, groupID, userIDs)
+	if result.Error != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al quitar miembros en BD",
+			zap.String("group_id", groupID), zap.Error(result.Error))
+		tracing.RecordError(span, result.Error)
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *membershipRepository) ReadMemberIDs(ctx context.Context, groupID string, limit, offset int) ([]string, int64, error) {
+	ctx, span := startTableSpan(ctx, "MembershipRepository.ReadMemberIDs", "group_members")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&models.GroupMember{}).
+		Joins("JOIN users ON users.id = group_members.user_id AND users.deleted_at IS NULL").
+		Where("group_members.tenant_id = ? AND group_members.group_id = ?", tenantID, groupID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al contar miembros en BD",
+			zap.String("group_id", groupID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+
+	var userIDs []string
+	if err := query.Order("group_members.created_at, group_members.user_id").Limit(limit).Offset(offset).
+		Pluck("group_members.user_id", &userIDs).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener miembros de BD",
+			zap.String("group_id", groupID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+	return userIDs, total, nil
+}
+
+func (r *membershipRepository) ReadGroupIDs(ctx context.Context, userID string) ([]string, error) {
+	ctx, span := startTableSpan(ctx, "MembershipRepository.ReadGroupIDs", "group_members")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var groupIDs []string
+	if err := r.db.WithContext(ctx).Model(&models.GroupMember{}).
+		Where("tenant_id = ? AND user_id = ?", tenantID, userID).Order("group_id").
+		Pluck("group_id", &groupIDs).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener grupos del usuario en BD",
+			zap.String("user_id", userID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return groupIDs, nil
+}
@@ -0,0 +1,49 @@
+package client_test
+
+import (
+	"testing"
+	"users-api/src/client"
+	"users-api/src/client/clienttest"
+	"users-api/src/config/db"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMemoryGroupRepositoryContract(t *testing.T) {
+	clienttest.RunGroupRepositoryContract(t, func(t *testing.T) clienttest.GroupRepositories {
+		users := client.NewMemoryUserRepository()
+		groups, memberships := client.NewMemoryGroupRepositories(users)
+		return clienttest.GroupRepositories{Users: users, Groups: groups, Memberships: memberships}
+	})
+}
+
+func TestSQLiteGroupRepositoryContract(t *testing.T) {
+	clienttest.RunGroupRepositoryContract(t, func(t *testing.T) clienttest.GroupRepositories {
+		sqliteDB, err := db.ConnectSQLite(":memory:", zap.NewNop())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := sqliteDB.DB()
+			sqlDB.Close()
+		})
+		return clienttest.GroupRepositories{
+			Users:       client.NewUserRepository(sqliteDB, zap.NewNop()),
+			Groups:      client.NewGroupRepository(sqliteDB, zap.NewNop()),
+			Memberships: client.NewMembershipRepository(sqliteDB, zap.NewNop()),
+		}
+	})
+}
+
+// TestPostgresGroupRepositoryContract corre contra una base real solo si TEST_POSTGRES_URI está definida
+func TestPostgresGroupRepositoryContract(t *testing.T) {
+	postgresDB := openPostgres(t)
+
+	clienttest.RunGroupRepositoryContract(t, func(t *testing.T) clienttest.GroupRepositories {
+		require.NoError(t, postgresDB.Exec("TRUNCATE TABLE users, groups CASCADE").Error)
+		return clienttest.GroupRepositories{
+			Users:       client.NewUserRepository(postgresDB, zap.NewNop()),
+			Groups:      client.NewGroupRepository(postgresDB, zap.NewNop()),
+			Memberships: client.NewMembershipRepository(postgresDB, zap.NewNop()),
+		}
+	})
+}
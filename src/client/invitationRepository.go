@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"time"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/models"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InvitationRepository opera sobre las invitaciones de la organización del principal de ctx, como UserRepository
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	// ReadPending devuelve las invitaciones pendientes en now, de la que vence primero a la última,
+	// desde offset, y el total de pendientes de la organización
+	ReadPending(ctx context.Context, now time.Time, limit, offset int) ([]models.Invitation, int64, error)
+	// ReadPendingByEmail devuelve la invitación pendiente en now para email, si hay alguna
+	ReadPendingByEmail(ctx context.Context, email string, now time.Time) (*models.Invitation, error)
+	ReadOne(ctx context.Context, id string) (*models.Invitation, error)
+	// MarkAccepted y Revoke cierran la invitación si sigue pendiente en at; si no, devuelven gorm.ErrRecordNotFound
+	MarkAccepted(ctx context.Context, id string, at time.Time) error
+	Revoke(ctx context.Context, id string, at time.Time) error
+}
+
+type invitationRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewInvitationRepository(db *gorm.DB, logger *zap.Logger) InvitationRepository {
+	return &invitationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// pending filtra las invitaciones de tenantID que siguen pendientes en now
+func (r *invitationRepository) pending(ctx context.Context, tenantID string, now time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.Invitation{}).
+		Where("tenant_id = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", tenantID, now)
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	ctx, span := startTableSpan(ctx, "InvitationRepository.Create", "invitations")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	invitation.TenantID = tenantID
+	if err := r.db.WithContext(ctx).Create(invitation).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al crear invitación en BD",
+			zap.String("email", invitation.Email), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+func (r *invitationRepository) ReadPending(ctx context.Context, now time.Time, limit, offset int) ([]models.Invitation, int64, error) {
+	ctx, span := startTableSpan(ctx, "InvitationRepository.ReadPending", "invitations")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := r.pending(ctx, tenantID, now).Count(&total).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al contar invitaciones en BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+
+	var invitations []models.Invitation
+	if err := r.pending(ctx, tenantID, now).Order("expires_at, id").Limit(limit).Offset(offset).Find(&invitations).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener invitaciones de BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, 0, err
+	}
+	return invitations, total, nil
+}
+
+func (r *invitationRepository) ReadPendingByEmail(ctx context.Context, email string, now time.Time) (*models.Invitation, error) {
+	ctx, span := startTableSpan(ctx, "InvitationRepository.ReadPendingByEmail", "invitations")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var invitation models.Invitation
+	if err := r.pending(ctx, tenantID, now).First(&invitation, "email = ?", email).Error; err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) ReadOne(ctx context.Context, id string) (*models.Invitation, error) {
+	ctx, span := startTableSpan(ctx, "InvitationRepository.ReadOne", "invitations")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var invitation models.Invitation
+	if err := r.db.WithContext(ctx).First(&invitation, "tenant_id = ? AND id = ?", tenantID, id).Error; err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) MarkAccepted(ctx context.Context, id string, at time.Time) error {
+	ctx, span := startTableSpan(ctx, "InvitationRepository.MarkAccepted", "invitations")
+	defer span.End()
+	return r.close(ctx, span, id, "accepted_at", at)
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	ctx, span := startTableSpan(ctx, "InvitationRepository.Revoke", "invitations")
+	defer span.End()
+	return r.close(ctx, span, id, "revoked_at", at)
+}
+
+// close completa column con at solo si la invitación sigue pendiente, para que dos solicitudes
+// simultáneas no cierren la misma invitación
+func (r *invitationRepository) close(ctx context.Context, span trace.Span, id, column string, at time.Time) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	result := r.pending(ctx, tenantID, at).Where("id = ?", id).Update(column, at)
+	if result.Error != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al cerrar invitación en BD",
+			zap.String("id", id), zap.String("column", column), zap.Error(result.Error))
+		tracing.RecordError(span, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"users-api/src/models"
+
+	"gorm.io/gorm"
+)
+
+// memoryGroupRepository guarda los grupos en memoria, con nombre único por organización como en BD
+type memoryGroupRepository struct {
+	mu      sync.RWMutex
+	groups  map[string]models.Group
+	members *memoryMembershipRepository
+}
+
+// NewMemoryGroupRepositories crea los repositorios de grupos y miembros en memoria. Comparten el
+// estado para que eliminar un grupo elimine sus miembros, y consultan users para no contar como
+// miembros a los usuarios eliminados
+func NewMemoryGroupRepositories(users UserRepository) (GroupRepository, MembershipRepository) {
+	members := &memoryMembershipRepository{users: users, members: map[string]models.GroupMember{}}
+	return &memoryGroupRepository{groups: map[string]models.Group{}, members: members}, members
+}
+
+func (r *memoryGroupRepository) Create(ctx context.Context, group *models.Group) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.groups[group.ID]; ok {
+		return fmt.Errorf("%w: id %s", gorm.ErrDuplicatedKey, group.ID)
+	}
+	for _, existing := range r.groups {
+		if existing.TenantID == tenantID && existing.Name == group.Name {
+			return fmt.Errorf("%w: name %s", gorm.ErrDuplicatedKey, group.Name)
+		}
+	}
+
+	group.TenantID = tenantID
+	now := time.Now()
+	group.CreatedAt = now
+	group.UpdatedAt = now
+	r.groups[group.ID] = *group
+	return nil
+}
+
+func (r *memoryGroupRepository) ReadPage(ctx context.Context, limit, offset int) ([]models.Group, int64, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := r.filter(tenantID, func(models.Group) bool { return true })
+	return page(groups, limit, offset), int64(len(groups)), nil
+}
+
+func (r *memoryGroupRepository) ReadList(ctx context.Context, ids []string) ([]models.Group, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filter(tenantID, func(group models.Group) bool { return wanted[group.ID] }), nil
+}
+
+func (r *memoryGroupRepository) ReadOne(ctx context.Context, id string) (*models.Group, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	group, ok := r.groups[id]
+	if !ok || group.TenantID != tenantID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &group, nil
+}
+
+func (r *memoryGroupRepository) ReadByName(ctx context.Context, name string) (*models.Group, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groups := r.filter(tenantID, func(group models.Group) bool { return group.Name == name })
+	if len(groups) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &groups[0], nil
+}
+
+func (r *memoryGroupRepository) Update(ctx context.Context, id string, group *models.Group) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.groups[id]
+	if !ok || existing.TenantID != tenantID {
+		return nil
+	}
+	for otherID, other := range r.groups {
+		if otherID != id && other.TenantID == tenantID && other.Name == group.Name {
+			return fmt.Errorf("%w: name %s", gorm.ErrDuplicatedKey, group.Name)
+		}
+	}
+
+	existing.Name = group.Name
+	existing.Description = group.Description
+	existing.UpdatedAt = time.Now()
+	r.groups[id] = existing
+	return nil
+}
+
+func (r *memoryGroupRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	group, ok := r.groups[id]
+	if !ok || group.TenantID != tenantID {
+		return nil
+	}
+	delete(r.groups, id)
+
+	r.members.mu.Lock()
+	defer r.members.mu.Unlock()
+	for key, member := range r.members.members {
+		if member.GroupID == id {
+			delete(r.members.members, key)
+		}
+	}
+	return nil
+}
+
+// filter devuelve los grupos de tenantID que cumplen match, ordenados por nombre. Requiere r.mu tomado
+func (r *memoryGroupRepository) filter(tenantID string, match func(models.Group) bool) []models.Group {
+	groups := []models.Group{}
+	for _, group := range r.groups {
+		if group.TenantID == tenantID && match(group) {
+			groups = append(groups, group)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups
+}
+
+type memoryMembershipRepository struct {
+	mu      sync.RWMutex
+	users   UserRepository
+	members map[string]models.GroupMember
+}
+
+func membershipKey(groupID, userID string) string {
+	return groupID + "/" + userID
+}
+
+func (r *memoryMembershipRepository) AddMembers(ctx context.Context, groupID string, userIDs []string) (int64, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var added int64
+	now := time.Now()
+	for _, userID := range userIDs {
+		key := membershipKey(groupID, userID)
+		if _, ok := r.members[key]; ok {
+			continue
+		}
+		r.members[key] = models.GroupMember{GroupID: groupID, UserID: userID, TenantID: tenantID, CreatedAt: now}
+		added++
+	}
+	return added, nil
+}
+
+func (r *memoryMembershipRepository) RemoveMembers(ctx context.Context, groupID string, userIDs []string) (int64, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var removed int64
+	for _, userID := range userIDs {
+		key := membershipKey(groupID, userID)
+		if member, ok := r.members[key]; ok && member.TenantID == tenantID {
+			delete(r.members, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (r *memoryMembershipRepository) ReadMemberIDs(ctx context.Context, groupID string, limit, offset int) ([]string, int64, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	var members []models.GroupMember
+	for _, member := range r.members {
+		if member.TenantID == tenantID && member.GroupID == groupID {
+			members = append(members, member)
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(members) == 0 {
+		return []string{}, 0, nil
+	}
+
+	// Como el JOIN con users en BD, los usuarios eliminados no son miembros
+	userIDs := make([]string, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	users, err := r.users.GetUsersList(ctx, userIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	existing := make(map[string]bool, len(users))
+	for _, user := range users {
+		existing[user.ID] = true
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].CreatedAt.Equal(members[j].CreatedAt) {
+			return members[i].CreatedAt.Before(members[j].CreatedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	memberIDs := []string{}
+	for _, member := range members {
+		if existing[member.UserID] {
+			memberIDs = append(memberIDs, member.UserID)
+		}
+	}
+	return page(memberIDs, limit, offset), int64(len(memberIDs)), nil
+}
+
+func (r *memoryMembershipRepository) ReadGroupIDs(ctx context.Context, userID string) ([]string, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	groupIDs := []string{}
+	for _, member := range r.members {
+		if member.TenantID == tenantID && member.UserID == userID {
+			groupIDs = append(groupIDs, member.GroupID)
+		}
+	}
+	sort.Strings(groupIDs)
+	return groupIDs, nil
+}
+
+// page aplica limit y offset como lo hace SQL; un limit negativo no limita
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"users-api/src/models"
+
+	"gorm.io/gorm"
+)
+
+// memoryInvitationRepository guarda las invitaciones en memoria
+type memoryInvitationRepository struct {
+	mu          sync.RWMutex
+	invitations map[string]models.Invitation
+}
+
+func NewMemoryInvitationRepository() InvitationRepository {
+	return &memoryInvitationRepository{invitations: map[string]models.Invitation{}}
+}
+
+func (r *memoryInvitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.invitations[invitation.ID]; ok {
+		return fmt.Errorf("%w: id %s", gorm.ErrDuplicatedKey, invitation.ID)
+	}
+
+	invitation.TenantID = tenantID
+	invitation.CreatedAt = time.Now()
+	r.invitations[invitation.ID] = *invitation
+	return nil
+}
+
+// pending devuelve las invitaciones de tenantID pendientes en now, en el orden de ReadPending
+func (r *memoryInvitationRepository) pending(tenantID string, now time.Time) []models.Invitation {
+	var invitations []models.Invitation
+	for _, invitation := range r.invitations {
+		if invitation.TenantID == tenantID && invitation.Pending(now) {
+			invitations = append(invitations, invitation)
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool {
+		if !invitations[i].ExpiresAt.Equal(invitations[j].ExpiresAt) {
+			return invitations[i].ExpiresAt.Before(invitations[j].ExpiresAt)
+		}
+		return invitations[i].ID < invitations[j].ID
+	})
+	return invitations
+}
+
+func (r *memoryInvitationRepository) ReadPending(ctx context.Context, now time.Time, limit, offset int) ([]models.Invitation, int64, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	invitations := r.pending(tenantID, now)
+	return page(invitations, limit, offset), int64(len(invitations)), nil
+}
+
+func (r *memoryInvitationRepository) ReadPendingByEmail(ctx context.Context, email string, now time.Time) (*models.Invitation, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, invitation := range r.pending(tenantID, now) {
+		if invitation.Email == email {
+			return &invitation, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryInvitationRepository) ReadOne(ctx context.Context, id string) (*models.Invitation, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	invitation, ok := r.invitations[id]
+	if !ok || invitation.TenantID != tenantID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &invitation, nil
+}
+
+func (r *memoryInvitationRepository) MarkAccepted(ctx context.Context, id string, at time.Time) error {
+	return r.close(ctx, id, at, func(invitation *models.Invitation) { invitation.AcceptedAt = &at })
+}
+
+func (r *memoryInvitationRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	return r.close(ctx, id, at, func(invitation *models.Invitation) { invitation.RevokedAt = &at })
+}
+
+func (r *memoryInvitationRepository) close(ctx context.Context, id string, at time.Time, set func(*models.Invitation)) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitation, ok := r.invitations[id]
+	if !ok || invitation.TenantID != tenantID || !invitation.Pending(at) {
+		return gorm.ErrRecordNotFound
+	}
+	set(&invitation)
+	r.invitations[id] = invitation
+	return nil
+}
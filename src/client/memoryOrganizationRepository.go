@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"users-api/src/models"
+
+	"gorm.io/gorm"
+)
+
+// memoryOrganizationRepository guarda las organizaciones en memoria, empezando por DefaultOrganization
+type memoryOrganizationRepository struct {
+	mu            sync.RWMutex
+	organizations map[string]models.Organization
+}
+
+func NewMemoryOrganizationRepository() OrganizationRepository {
+	organization := DefaultOrganization()
+	organization.CreatedAt = time.Now()
+	organization.UpdatedAt = organization.CreatedAt
+	return &memoryOrganizationRepository{
+		organizations: map[string]models.Organization{organization.ID: *organization},
+	}
+}
+
+func (r *memoryOrganizationRepository) Create(ctx context.Context, organization *models.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.organizations[organization.ID]; ok {
+		return fmt.Errorf("%w: id %s", gorm.ErrDuplicatedKey, organization.ID)
+	}
+	if organization.APIKeyHash != nil {
+		for _, existing := range r.organizations {
+			if existing.APIKeyHash != nil && *existing.APIKeyHash == *organization.APIKeyHash {
+				return fmt.Errorf("%w: api_key_hash", gorm.ErrDuplicatedKey)
+			}
+		}
+	}
+
+	now := time.Now()
+	organization.CreatedAt = now
+	organization.UpdatedAt = now
+	r.organizations[organization.ID] = *organization
+	return nil
+}
+
+func (r *memoryOrganizationRepository) ReadAll(ctx context.Context) ([]models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	organizations := make([]models.Organization, 0, len(r.organizations))
+	for _, organization := range r.organizations {
+		organizations = append(organizations, organization)
+	}
+	sort.Slice(organizations, func(i, j int) bool { return organizations[i].ID < organizations[j].ID })
+	return organizations, nil
+}
+
+func (r *memoryOrganizationRepository) ReadOne(ctx context.Context, id string) (*models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	organization, ok := r.organizations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &organization, nil
+}
+
+func (r *memoryOrganizationRepository) ReadByAPIKeyHash(ctx context.Context, apiKeyHash string) (*models.Organization, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, organization := range r.organizations {
+		if organization.APIKeyHash != nil && *organization.APIKeyHash == apiKeyHash {
+			return &organization, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOrganizationRepository) UpdateAPIKeyHash(ctx context.Context, id string, apiKeyHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	organization, ok := r.organizations[id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	organization.APIKeyHash = &apiKeyHash
+	organization.UpdatedAt = time.Now()
+	r.organizations[id] = organization
+	return nil
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"sort"
+	"users-api/src/auth"
+	"users-api/src/models"
+
+	"gorm.io/gorm"
+)
+
+// memoryRoleRepository expone los roles de auth.DefaultRoles, para tests y desarrollo local
+type memoryRoleRepository struct {
+	roles map[string]models.Role
+}
+
+func NewMemoryRoleRepository() RoleRepository {
+	roles := map[string]models.Role{}
+	for _, role := range DefaultRoles() {
+		roles[role.Name] = role
+	}
+	return &memoryRoleRepository{roles: roles}
+}
+
+// DefaultRoles arma los modelos de auth.DefaultRoles, para sembrar las bases que no usan las migraciones SQL
+func DefaultRoles() []models.Role {
+	roles := make([]models.Role, 0, len(auth.DefaultRoles))
+	for name, permissions := range auth.DefaultRoles {
+		role := models.Role{Name: name, Description: auth.RoleDescriptions[name]}
+		for _, permission := range permissions {
+			role.Permissions = append(role.Permissions, models.Permission{Name: permission, Description: auth.Permissions[permission]})
+		}
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+func (r *memoryRoleRepository) ReadAll(ctx context.Context) ([]models.Role, error) {
+	roles := make([]models.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) ReadOne(ctx context.Context, name string) (*models.Role, error) {
+	role, ok := r.roles[name]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &role, nil
+}
@@ -0,0 +1,310 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"users-api/src/models"
+
+	"gorm.io/gorm"
+)
+
+// memoryUserRepository guarda los usuarios en memoria para tests y desarrollo local.
+// Reproduce el comportamiento de GORM: borrado lógico, email único por organización y gorm.ErrRecordNotFound.
+type memoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[string]models.User
+	// history guarda los hashes anteriores de cada usuario, del más antiguo al más reciente
+	history map[string][]string
+}
+
+func NewMemoryUserRepository() UserRepository {
+	return &memoryUserRepository{
+		users:   map[string]models.User{},
+		history: map[string][]string{},
+	}
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID]; ok {
+		return fmt.Errorf("%w: id %s", gorm.ErrDuplicatedKey, user.ID)
+	}
+	// El índice único de email en BD también incluye a los usuarios borrados lógicamente
+	for _, existing := range r.users {
+		if existing.TenantID == tenantID && existing.Email == user.Email {
+			return fmt.Errorf("%w: email %s", gorm.ErrDuplicatedKey, user.Email)
+		}
+	}
+
+	user.TenantID = tenantID
+	if user.Status == "" {
+		user.Status = models.UserStatusActive
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *memoryUserRepository) ReadAll(ctx context.Context) ([]models.User, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.filter(tenantID, func(models.User) bool { return true }), nil
+}
+
+func (r *memoryUserRepository) ReadRecent(ctx context.Context, limit int) ([]models.User, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := r.filter(tenantID, func(models.User) bool { return true })
+	sort.SliceStable(users, func(i, j int) bool { return users[i].UpdatedAt.After(users[j].UpdatedAt) })
+	if limit >= 0 && len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) GetUsersList(ctx context.Context, ids []string) ([]models.User, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		wanted[id] = struct{}{}
+	}
+	return r.filter(tenantID, func(user models.User) bool {
+		_, ok := wanted[user.ID]
+		return ok
+	}), nil
+}
+
+func (r *memoryUserRepository) ReadByEmail(ctx context.Context, email string) (*models.User, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := r.filter(tenantID, func(user models.User) bool { return user.Email == email })
+	if len(users) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &users[0], nil
+}
+
+func (r *memoryUserRepository) ReadOne(ctx context.Context, id string) (*models.User, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID || user.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+// Update solo modifica los campos con valor distinto de cero, igual que Updates de GORM con un struct
+func (r *memoryUserRepository) Update(ctx context.Context, id string, user *models.User) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.users[id]
+	if !ok || existing.TenantID != tenantID || existing.DeletedAt.Valid {
+		return nil
+	}
+
+	if user.Email != "" && user.Email != existing.Email {
+		for otherID, other := range r.users {
+			if otherID != id && other.TenantID == tenantID && other.Email == user.Email {
+				return fmt.Errorf("%w: email %s", gorm.ErrDuplicatedKey, user.Email)
+			}
+		}
+	}
+
+	mergeNonZero(&existing, user)
+	existing.UpdatedAt = time.Now()
+	user.UpdatedAt = existing.UpdatedAt
+	r.users[id] = existing
+	return nil
+}
+
+func (r *memoryUserRepository) UpdateStatus(ctx context.Context, id, from, to, reason string, at time.Time) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID || user.DeletedAt.Valid || user.Status != from {
+		return gorm.ErrRecordNotFound
+	}
+	user.Status = to
+	user.StatusReason = reason
+	user.StatusChangedAt = &at
+	user.UpdatedAt = time.Now()
+	r.users[id] = user
+	return nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(ctx context.Context, id, from, to string) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID || user.DeletedAt.Valid || user.Password != from {
+		return gorm.ErrRecordNotFound
+	}
+	user.Password = to
+	user.UpdatedAt = time.Now()
+	r.users[id] = user
+	return nil
+}
+
+func (r *memoryUserRepository) ChangePassword(ctx context.Context, id, from, to string, at time.Time, keep int) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID || user.DeletedAt.Valid || user.Password != from {
+		return gorm.ErrRecordNotFound
+	}
+	user.Password = to
+	user.PasswordChangedAt = &at
+	user.UpdatedAt = time.Now()
+	r.users[id] = user
+
+	history := append(r.history[id], from)
+	if len(history) > keep {
+		history = history[len(history)-max(keep, 0):]
+	}
+	r.history[id] = history
+	return nil
+}
+
+func (r *memoryUserRepository) ReadPasswordHistory(ctx context.Context, id string, limit int) ([]string, error) {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if user, ok := r.users[id]; !ok || user.TenantID != tenantID {
+		return nil, nil
+	}
+	hashes := []string{}
+	history := r.history[id]
+	for i := len(history) - 1; i >= 0 && len(hashes) < limit; i-- {
+		hashes = append(hashes, history[i])
+	}
+	return hashes, nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id string) error {
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.TenantID != tenantID || user.DeletedAt.Valid {
+		return nil
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.users[id] = user
+	return nil
+}
+
+// filter devuelve copias de los usuarios no borrados de tenantID que cumplen la condición, ordenados por creación
+func (r *memoryUserRepository) filter(tenantID string, match func(models.User) bool) []models.User {
+	users := []models.User{}
+	for _, user := range r.users {
+		if user.TenantID == tenantID && !user.DeletedAt.Valid && match(user) {
+			users = append(users, user)
+		}
+	}
+	sort.SliceStable(users, func(i, j int) bool { return users[i].CreatedAt.Before(users[j].CreatedAt) })
+	return users
+}
+
+func mergeNonZero(dst *models.User, src *models.User) {
+	if src.Name != "" {
+		dst.Name = src.Name
+	}
+	if src.Lastname != "" {
+		dst.Lastname = src.Lastname
+	}
+	if !src.Birthdate.IsZero() {
+		dst.Birthdate = src.Birthdate
+	}
+	if src.Role != "" {
+		dst.Role = src.Role
+	}
+	if src.Email != "" {
+		dst.Email = src.Email
+	}
+	if src.Password != "" {
+		dst.Password = src.Password
+	}
+	if src.Avatar != "" {
+		dst.Avatar = src.Avatar
+	}
+}
@@ -0,0 +1,13 @@
+package client_test
+
+import (
+	"testing"
+	"users-api/src/client"
+	"users-api/src/client/clienttest"
+)
+
+func TestMemoryUserRepositoryContract(t *testing.T) {
+	clienttest.RunUserRepositoryContract(t, func(t *testing.T) client.UserRepository {
+		return client.NewMemoryUserRepository()
+	})
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// OrganizationRepository administra las organizaciones, que están por encima de los tenants y no se filtran por ellos
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization *models.Organization) error
+	ReadAll(ctx context.Context) ([]models.Organization, error)
+	ReadOne(ctx context.Context, id string) (*models.Organization, error)
+	ReadByAPIKeyHash(ctx context.Context, apiKeyHash string) (*models.Organization, error)
+	UpdateAPIKeyHash(ctx context.Context, id string, apiKeyHash string) error
+}
+
+type organizationRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewOrganizationRepository(db *gorm.DB, logger *zap.Logger) OrganizationRepository {
+	return &organizationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// DefaultOrganization es la organización que crean las migraciones para los usuarios existentes
+func DefaultOrganization() *models.Organization {
+	return &models.Organization{ID: models.DefaultOrganizationID, Name: "Default"}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, organization *models.Organization) error {
+	ctx, span := startTableSpan(ctx, "OrganizationRepository.Create", "organizations")
+	defer span.End()
+
+	if err := r.db.WithContext(ctx).Create(organization).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al crear organización en BD",
+			zap.String("id", organization.ID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+func (r *organizationRepository) ReadAll(ctx context.Context) ([]models.Organization, error) {
+	ctx, span := startTableSpan(ctx, "OrganizationRepository.ReadAll", "organizations")
+	defer span.End()
+
+	var organizations []models.Organization
+	if err := r.db.WithContext(ctx).Order("id").Find(&organizations).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener organizaciones de BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return organizations, nil
+}
+
+func (r *organizationRepository) ReadOne(ctx context.Context, id string) (*models.Organization, error) {
+	ctx, span := startTableSpan(ctx, "OrganizationRepository.ReadOne", "organizations")
+	defer span.End()
+
+	var organization models.Organization
+	if err := r.db.WithContext(ctx).First(&organization, "id = ?", id).Error; err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) ReadByAPIKeyHash(ctx context.Context, apiKeyHash string) (*models.Organization, error) {
+	ctx, span := startTableSpan(ctx, "OrganizationRepository.ReadByAPIKeyHash", "organizations")
+	defer span.End()
+
+	var organization models.Organization
+	if err := r.db.WithContext(ctx).First(&organization, "api_key_hash = ?", apiKeyHash).Error; err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) UpdateAPIKeyHash(ctx context.Context, id string, apiKeyHash string) error {
+	ctx, span := startTableSpan(ctx, "OrganizationRepository.UpdateAPIKeyHash", "organizations")
+	defer span.End()
+
+	result := r.db.WithContext(ctx).Model(&models.Organization{}).Where("id = ?", id).Update("api_key_hash", apiKeyHash)
+	if result.Error != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al actualizar la API key de la organización",
+			zap.String("id", id), zap.Error(result.Error))
+		tracing.RecordError(span, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	ReadAll(ctx context.Context) ([]models.Role, error)
+	ReadOne(ctx context.Context, name string) (*models.Role, error)
+}
+
+type roleRepository struct {
+	db     *gorm.DB
+	logger *zap.Logger
+}
+
+func NewRoleRepository(db *gorm.DB, logger *zap.Logger) RoleRepository {
+	return &roleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *roleRepository) ReadAll(ctx context.Context) ([]models.Role, error) {
+	ctx, span := startTableSpan(ctx, "RoleRepository.ReadAll", "roles")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+
+	var roles []models.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al obtener los roles de BD", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) ReadOne(ctx context.Context, name string) (*models.Role, error) {
+	ctx, span := startTableSpan(ctx, "RoleRepository.ReadOne", "roles")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+
+	var role models.Role
+	if err := r.db.WithContext(ctx).Preload("Permissions").First(&role, "name = ?", name).Error; err != nil {
+		logger.Warn("[USERS-API][Repository]: Error al buscar rol en BD", zap.String("role", name), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return &role, nil
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"users-api/src/auth"
+)
+
+// ErrNoTenant se devuelve cuando la solicitud no tiene organización. Los repositorios de usuarios
+// no consultan sin ella, para que un olvido no exponga los usuarios de otra organización
+var ErrNoTenant = errors.New("client: la solicitud no tiene organización")
+
+// tenantFromContext devuelve la organización del principal de ctx, ver auth.TenantFromContext
+func tenantFromContext(ctx context.Context) (string, error) {
+	tenantID, ok := auth.TenantFromContext(ctx)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	return tenantID, nil
+}
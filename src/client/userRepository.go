@@ -2,19 +2,56 @@ package client
 
 import (
 	"context"
+	stderrors "errors"
+	"time"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
 	"users-api/src/models"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+var tracer = otel.Tracer("users-api/src/client")
+
+// startSpan crea el span de una consulta a PostgreSQL sobre la tabla users
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return startTableSpan(ctx, name, "users")
+}
+
+// startTableSpan crea el span de una consulta a PostgreSQL sobre table
+func startTableSpan(ctx context.Context, name, table string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBCollectionName(table)))
+}
+
+// UserRepository opera sobre los usuarios de la organización del principal de ctx
+// (auth.TenantFromContext) y devuelve ErrNoTenant si no la tiene
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	ReadAll(ctx context.Context) ([]models.User, error)
+	ReadRecent(ctx context.Context, limit int) ([]models.User, error)
 	GetUsersList(ctx context.Context, ids []string) ([]models.User, error)
 	ReadByEmail(ctx context.Context, email string) (*models.User, error)
 	ReadOne(ctx context.Context, id string) (*models.User, error)
 	Update(ctx context.Context, id string, user *models.User) error
+	// UpdateStatus pasa al usuario del estado from a to solo si sigue en from; si no, devuelve
+	// gorm.ErrRecordNotFound, así dos cambios simultáneos no se pisan
+	UpdateStatus(ctx context.Context, id, from, to, reason string, at time.Time) error
+	// UpdatePassword reemplaza el hash de la contraseña from por to solo si no cambió mientras tanto;
+	// si cambió devuelve gorm.ErrRecordNotFound
+	UpdatePassword(ctx context.Context, id, from, to string) error
+	// ChangePassword reemplaza la contraseña from por to como UpdatePassword, registra at como fecha del
+	// cambio y guarda from en el historial, del que conserva las keep más recientes
+	ChangePassword(ctx context.Context, id, from, to string, at time.Time, keep int) error
+	// ReadPasswordHistory devuelve los hashes de hasta limit contraseñas anteriores, de la más reciente
+	// a la más antigua
+	ReadPasswordHistory(ctx context.Context, id string, limit int) ([]string, error)
 	Delete(ctx context.Context, id string) error
 }
 
@@ -31,115 +68,338 @@ func NewUserRepository(db *gorm.DB, logger *zap.Logger) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
-	r.logger.Info("[USERS-API][Repository]: Iniciando creación de usuario en BD",
+	ctx, span := startSpan(ctx, "UserRepository.Create")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API][Repository]: Iniciando creación de usuario en BD",
 		zap.String("email", user.Email))
 
-	if err := r.db.Create(user).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al crear usuario en BD",
+	user.TenantID = tenantID
+	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al crear usuario en BD",
 			zap.String("email", user.Email),
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Usuario creado exitosamente en BD",
+	logger.Info("[USERS-API][Repository]: Usuario creado exitosamente en BD",
 		zap.String("id", user.ID))
 	return nil
 }
 
 func (r *userRepository) ReadAll(ctx context.Context) ([]models.User, error) {
-	r.logger.Info("[USERS-API][Repository]: Iniciando búsqueda de todos los usuarios en BD")
+	ctx, span := startSpan(ctx, "UserRepository.ReadAll")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API][Repository]: Iniciando búsqueda de todos los usuarios en BD")
+
+	var users []models.User
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Find(&users).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al obtener todos los usuarios de BD",
+			zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API][Repository]: Usuarios obtenidos exitosamente de BD",
+		zap.Int("count", len(users)))
+	return users, nil
+}
+
+// ReadRecent devuelve los usuarios con actividad más reciente, ordenados por fecha de actualización
+func (r *userRepository) ReadRecent(ctx context.Context, limit int) ([]models.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.ReadRecent")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API][Repository]: Buscando usuarios recientes en BD",
+		zap.Int("limit", limit))
 
 	var users []models.User
-	if err := r.db.Find(&users).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al obtener todos los usuarios de BD",
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", tenantID).Order("updated_at DESC").Limit(limit).Find(&users).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al obtener usuarios recientes de BD",
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Usuarios obtenidos exitosamente de BD",
+	logger.Info("[USERS-API][Repository]: Usuarios recientes obtenidos exitosamente de BD",
 		zap.Int("count", len(users)))
 	return users, nil
 }
 
 func (r *userRepository) GetUsersList(ctx context.Context, ids []string) ([]models.User, error) {
-	r.logger.Info("[USERS-API][Repository]: Buscando lista de usuarios por IDs en BD",
+	ctx, span := startSpan(ctx, "UserRepository.GetUsersList")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API][Repository]: Buscando lista de usuarios por IDs en BD",
 		zap.Strings("ids", ids))
 
 	var users []models.User
-	if err := r.db.Where("id IN ?", ids).Find(&users).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al obtener lista de usuarios de BD",
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id IN ?", tenantID, ids).Find(&users).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al obtener lista de usuarios de BD",
 			zap.Strings("ids", ids),
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Lista de usuarios obtenida exitosamente de BD",
+	logger.Info("[USERS-API][Repository]: Lista de usuarios obtenida exitosamente de BD",
 		zap.Int("count", len(users)))
 	return users, nil
 }
 
 func (r *userRepository) ReadByEmail(ctx context.Context, email string) (*models.User, error) {
-	r.logger.Info("[USERS-API][Repository]: Buscando usuario por email en BD",
+	ctx, span := startSpan(ctx, "UserRepository.ReadByEmail")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API][Repository]: Buscando usuario por email en BD",
 		zap.String("email", email))
 
 	var user models.User
-	if err := r.db.First(&user, "email = ?", email).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al buscar usuario por email en BD",
+	if err := r.db.WithContext(ctx).First(&user, "tenant_id = ? AND email = ?", tenantID, email).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al buscar usuario por email en BD",
 			zap.String("email", email),
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Usuario encontrado exitosamente en BD",
+	logger.Info("[USERS-API][Repository]: Usuario encontrado exitosamente en BD",
 		zap.String("email", email))
 	return &user, nil
 }
 
 func (r *userRepository) ReadOne(ctx context.Context, id string) (*models.User, error) {
-	r.logger.Info("[USERS-API][Repository]: Buscando usuario por ID en BD",
+	ctx, span := startSpan(ctx, "UserRepository.ReadOne")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API][Repository]: Buscando usuario por ID en BD",
 		zap.String("id", id))
 
 	var user models.User
-	if err := r.db.First(&user, "id = ?", id).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al buscar usuario por ID en BD",
+	if err := r.db.WithContext(ctx).First(&user, "tenant_id = ? AND id = ?", tenantID, id).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al buscar usuario por ID en BD",
 			zap.String("id", id),
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Usuario encontrado exitosamente en BD",
+	logger.Info("[USERS-API][Repository]: Usuario encontrado exitosamente en BD",
 		zap.String("id", id))
 	return &user, nil
 }
 
 func (r *userRepository) Update(ctx context.Context, id string, user *models.User) error {
-	r.logger.Info("[USERS-API][Repository]: Iniciando actualización de usuario en BD",
+	ctx, span := startSpan(ctx, "UserRepository.Update")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API][Repository]: Iniciando actualización de usuario en BD",
 		zap.String("id", id))
 
-	if err := r.db.Where("id = ?", id).Updates(user).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al actualizar usuario en BD",
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND id = ?", tenantID, id).Omit("tenant_id").Updates(user).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al actualizar usuario en BD",
 			zap.String("id", id),
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Usuario actualizado exitosamente en BD",
+	logger.Info("[USERS-API][Repository]: Usuario actualizado exitosamente en BD",
 		zap.String("id", id))
 	return nil
 }
 
+func (r *userRepository) UpdateStatus(ctx context.Context, id, from, to, reason string, at time.Time) error {
+	ctx, span := startSpan(ctx, "UserRepository.UpdateStatus")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("tenant_id = ? AND id = ? AND status = ?", tenantID, id, from).
+		Updates(map[string]interface{}{"status": to, "status_reason": reason, "status_changed_at": at})
+	if result.Error != nil {
+		logger.Error("[USERS-API][Repository]: Error al actualizar el estado del usuario en BD",
+			zap.String("id", id),
+			zap.Error(result.Error))
+		tracing.RecordError(span, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("[USERS-API][Repository]: Estado del usuario actualizado en BD",
+		zap.String("id", id), zap.String("status", to))
+	return nil
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, id, from, to string) error {
+	ctx, span := startSpan(ctx, "UserRepository.UpdatePassword")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("tenant_id = ? AND id = ? AND password = ?", tenantID, id, from).
+		Update("password", to)
+	if result.Error != nil {
+		logger.Error("[USERS-API][Repository]: Error al actualizar la contraseña del usuario en BD",
+			zap.String("id", id),
+			zap.Error(result.Error))
+		tracing.RecordError(span, result.Error)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	logger.Info("[USERS-API][Repository]: Contraseña del usuario actualizada en BD", zap.String("id", id))
+	return nil
+}
+
+func (r *userRepository) ChangePassword(ctx context.Context, id, from, to string, at time.Time, keep int) error {
+	ctx, span := startSpan(ctx, "UserRepository.ChangePassword")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("tenant_id = ? AND id = ? AND password = ?", tenantID, id, from).
+			Updates(map[string]interface{}{"password": to, "password_changed_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if keep <= 0 {
+			return tx.Delete(&models.PasswordHistory{}, "tenant_id = ? AND user_id = ?", tenantID, id).Error
+		}
+		entry := &models.PasswordHistory{ID: uuid.New().String(), TenantID: tenantID, UserID: id, Password: from, CreatedAt: at}
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		recent := tx.Model(&models.PasswordHistory{}).Select("id").
+			Where("tenant_id = ? AND user_id = ?", tenantID, id).Order("created_at DESC").Limit(keep)
+		return tx.Where("tenant_id = ? AND user_id = ? AND id NOT IN (?)", tenantID, id, recent).
+			Delete(&models.PasswordHistory{}).Error
+	})
+	if err != nil {
+		if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("[USERS-API][Repository]: Error al cambiar la contraseña del usuario en BD",
+				zap.String("id", id),
+				zap.Error(err))
+			tracing.RecordError(span, err)
+		}
+		return err
+	}
+
+	logger.Info("[USERS-API][Repository]: Contraseña del usuario cambiada en BD", zap.String("id", id))
+	return nil
+}
+
+func (r *userRepository) ReadPasswordHistory(ctx context.Context, id string, limit int) ([]string, error) {
+	ctx, span := startTableSpan(ctx, "UserRepository.ReadPasswordHistory", "password_history")
+	defer span.End()
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	var hashes []string
+	if err := r.db.WithContext(ctx).Model(&models.PasswordHistory{}).
+		Where("tenant_id = ? AND user_id = ?", tenantID, id).
+		Order("created_at DESC").Limit(limit).Pluck("password", &hashes).Error; err != nil {
+		log.FromContext(ctx, r.logger).Error("[USERS-API][Repository]: Error al obtener el historial de contraseñas en BD",
+			zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return hashes, nil
+}
+
 func (r *userRepository) Delete(ctx context.Context, id string) error {
-	r.logger.Info("[USERS-API][Repository]: Iniciando eliminación de usuario en BD",
+	ctx, span := startSpan(ctx, "UserRepository.Delete")
+	defer span.End()
+	logger := log.FromContext(ctx, r.logger)
+	tenantID, err := tenantFromContext(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API][Repository]: Iniciando eliminación de usuario en BD",
 		zap.String("id", id))
 
-	if err := r.db.Delete(&models.User{}, "id = ?", id).Error; err != nil {
-		r.logger.Error("[USERS-API][Repository]: Error al eliminar usuario en BD",
+	if err := r.db.WithContext(ctx).Delete(&models.User{}, "tenant_id = ? AND id = ?", tenantID, id).Error; err != nil {
+		logger.Error("[USERS-API][Repository]: Error al eliminar usuario en BD",
 			zap.String("id", id),
 			zap.Error(err))
+		tracing.RecordError(span, err)
 		return err
 	}
 
-	r.logger.Info("[USERS-API][Repository]: Usuario eliminado exitosamente de BD",
+	logger.Info("[USERS-API][Repository]: Usuario eliminado exitosamente de BD",
 		zap.String("id", id))
 	return nil
 }
@@ -0,0 +1,64 @@
+package client_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"users-api/src/client"
+	"users-api/src/client/clienttest"
+	"users-api/src/config/db"
+	"users-api/src/config/db/migrations"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSQLiteUserRepositoryContract(t *testing.T) {
+	clienttest.RunUserRepositoryContract(t, func(t *testing.T) client.UserRepository {
+		sqliteDB, err := db.ConnectSQLite(":memory:", zap.NewNop())
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := sqliteDB.DB()
+			sqlDB.Close()
+		})
+		return client.NewUserRepository(sqliteDB, zap.NewNop())
+	})
+}
+
+// openPostgres conecta con TEST_POSTGRES_URI, o saltea el test si no está definida. La base se migra
+// y se crea la organización clienttest.OtherTenant
+func openPostgres(t *testing.T) *gorm.DB {
+	t.Helper()
+	uri := os.Getenv("TEST_POSTGRES_URI")
+	if uri == "" {
+		t.Skip("TEST_POSTGRES_URI no está definida")
+	}
+
+	postgresDB, err := gorm.Open(postgres.Open(uri), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := postgresDB.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.NewMigrator(sqlDB, zap.NewNop())
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, postgresDB.Exec("INSERT INTO organizations (id, name) VALUES (?, ?) ON CONFLICT DO NOTHING",
+		clienttest.OtherTenant, clienttest.OtherTenant).Error)
+	return postgresDB
+}
+
+// TestPostgresUserRepositoryContract corre contra una base real solo si TEST_POSTGRES_URI está definida.
+// La tabla users se vacía antes de cada caso.
+func TestPostgresUserRepositoryContract(t *testing.T) {
+	postgresDB := openPostgres(t)
+
+	clienttest.RunUserRepositoryContract(t, func(t *testing.T) client.UserRepository {
+		require.NoError(t, postgresDB.Exec("TRUNCATE TABLE users CASCADE").Error)
+		return client.NewUserRepository(postgresDB, zap.NewNop())
+	})
+}
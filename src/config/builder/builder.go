@@ -1,65 +1,255 @@
 package builder
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	stdlog "log"
+	"net"
+	"net/http"
+	"users-api/src/auth"
+	"users-api/src/cache"
 	"users-api/src/client"
+	"users-api/src/config"
 	"users-api/src/config/db"
+	"users-api/src/config/db/migrations"
 	"users-api/src/config/log"
 	"users-api/src/config/redis"
+	"users-api/src/config/tracing"
 	"users-api/src/controllers"
+	"users-api/src/graph"
+	"users-api/src/grpcserver"
+	"users-api/src/metrics"
+	"users-api/src/middlewares"
 	"users-api/src/router"
 	"users-api/src/services"
+	"users-api/src/utils"
 
 	"github.com/gin-gonic/gin"
 	redisClient "github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
 type AppBuilder struct {
-	db             *gorm.DB
-	redisClient    *redisClient.Client
-	Logger         *zap.Logger
-	userRepo       client.UserRepository
-	userService    services.UserService
-	authService    services.AuthService
-	userController *controllers.UserController
-	authController *controllers.AuthController
-	router         *gin.Engine
+	config           *config.Config
+	db               *gorm.DB
+	migrator         *migrations.Migrator
+	redisClient      *redisClient.Client
+	cache            cache.Cache
+	Logger           *zap.Logger
+	userRepo         client.UserRepository
+	roleRepo         client.RoleRepository
+	organizationRepo client.OrganizationRepository
+	groupRepo        client.GroupRepository
+	membershipRepo   client.MembershipRepository
+	invitationRepo   client.InvitationRepository
+	hasher           utils.PasswordHasher
+	userService      services.UserService
+	roleService      services.RoleService
+	orgService       services.OrganizationService
+	groupService     services.GroupService
+	invitations      services.InvitationService
+	credentials      services.CredentialService
+	authService      services.AuthService
+	cacheService     services.CacheService
+	healthService    services.HealthService
+	tokenService     services.TokenService
+	userController   *controllers.UserController
+	authController   *controllers.AuthController
+	cacheController  *controllers.CacheController
+	healthController *controllers.HealthController
+	roleController   *controllers.RoleController
+	groupController  *controllers.GroupController
+	inviteController *controllers.InvitationController
+	graphqlHandler   http.Handler
+	router           *gin.Engine
+	server           *http.Server
+	grpcServer       *grpc.Server
+	shutdownTracing  func(context.Context) error
+	logOutput        io.Writer
 }
 
-func NewAppBuilder() *AppBuilder {
-	return &AppBuilder{}
+func NewAppBuilder(cfg *config.Config) *AppBuilder {
+	return &AppBuilder{config: cfg}
 }
 
-func BuildApp() *AppBuilder {
-	return NewAppBuilder().
+func BuildApp(cfg *config.Config) *AppBuilder {
+	return NewAppBuilder(cfg).BuildAll()
+}
+
+// WithLogOutput reemplaza la salida de los logs, se usa antes de BuildLogger. Lo usan los tests
+func (b *AppBuilder) WithLogOutput(w io.Writer) *AppBuilder {
+	b.logOutput = w
+	return b
+}
+
+// BuildAll arma la aplicación completa, como BuildApp, sobre un builder ya configurado
+func (b *AppBuilder) BuildAll() *AppBuilder {
+	return b.
 		BuildLogger().
+		BuildTracing().
 		BuildDBConnection().
+		BuildMigrator().
+		BuildRedisConnection().
+		BuildCache().
 		BuildUserRepo().
 		BuildUserService().
 		BuildUserController().
-		BuildRouter()
+		BuildRouter().
+		BuildServer().
+		BuildGRPCServer()
+}
+
+// BuildCLIApp arma las dependencias de los servicios sin router ni servidor, para los comandos administrativos
+func BuildCLIApp(cfg *config.Config) *AppBuilder {
+	return NewAppBuilder(cfg).
+		BuildLogger().
+		BuildDBConnection().
+		BuildMigrator().
+		BuildRedisConnection().
+		BuildCache().
+		BuildUserRepo().
+		BuildUserService()
 }
 
 func (b *AppBuilder) BuildLogger() *AppBuilder {
-	b.Logger = log.GetLogger()
+	opts := log.Options{
+		Mode:               b.config.LogMode,
+		Level:              b.config.LogLevel,
+		Sampling:           b.config.LogSampling,
+		SamplingInitial:    b.config.LogSamplingInitial,
+		SamplingThereafter: b.config.LogSamplingAfter,
+	}
+	if b.logOutput != nil {
+		opts.Output = zapcore.AddSync(b.logOutput)
+	}
+	var err error
+	b.Logger, err = log.NewLogger(opts)
+	if err != nil {
+		stdlog.Fatalf("[USERS-API] Error al inicializar el logger: %v", err)
+	}
 	b.Logger.Info("[USERS-API] Logger inicializado")
+	b.Logger.Info("[USERS-API] Configuración cargada", zap.Stringer("config", b.config))
+	return b
+}
+
+func (b *AppBuilder) BuildTracing() *AppBuilder {
+	var err error
+	b.shutdownTracing, err = tracing.Setup(context.Background(), tracing.Options{
+		Exporter:    b.config.TracingExporter,
+		File:        b.config.TracingFile,
+		Endpoint:    b.config.TracingEndpoint,
+		SampleRatio: b.config.TracingSampleRatio,
+	})
+	if err != nil {
+		b.Logger.Fatal("[USERS-API] Error al configurar el tracing", zap.Error(err))
+	}
+	b.Logger.Info("[USERS-API] Tracing inicializado", zap.String("exporter", b.config.TracingExporter))
 	return b
 }
 
 func (b *AppBuilder) BuildDBConnection() *AppBuilder {
 	var err error
-	b.db, err = db.ConnectDB(b.Logger)
+	switch b.config.DBDriver {
+	case config.DBDriverMemory:
+		b.Logger.Warn("[USERS-API] Usando repositorio en memoria, los datos se pierden al reiniciar")
+		return b
+	case config.DBDriverSQLite:
+		b.db, err = db.ConnectSQLite(b.config.SQLitePath, b.Logger)
+	default:
+		b.db, err = db.ConnectDB(b.config.PostgresURI.Value(), b.Logger)
+	}
 	if err != nil {
 		b.Logger.Fatal("[USERS-API] Error al conectar a la base de datos", zap.Error(err))
 	}
-	b.redisClient = redis.ConnectRedis()
+	if sqlDB, err := b.db.DB(); err == nil {
+		if err := metrics.RegisterDB(sqlDB, "users"); err != nil {
+			b.Logger.Warn("[USERS-API] No se pudieron registrar las métricas de la base de datos", zap.Error(err))
+		}
+	}
+	return b
+}
+
+// BuildMigrator prepara las migraciones y, si MIGRATE_ON_START está activo, aplica las pendientes
+func (b *AppBuilder) BuildMigrator() *AppBuilder {
+	// Las migraciones SQL son de PostgreSQL, SQLite crea el esquema con AutoMigrate
+	if b.config.DBDriver != config.DBDriverPostgres {
+		return b
+	}
+
+	sqlDB, err := b.db.DB()
+	if err != nil {
+		b.Logger.Fatal("[USERS-API] Error al obtener la conexión SQL", zap.Error(err))
+	}
+	b.migrator, err = migrations.NewMigrator(sqlDB, b.Logger)
+	if err != nil {
+		b.Logger.Fatal("[USERS-API] Error al cargar las migraciones", zap.Error(err))
+	}
+
+	if b.config.MigrateOnStart {
+		applied, err := b.migrator.Up(context.Background())
+		if err != nil {
+			b.Logger.Fatal("[USERS-API] Error al aplicar las migraciones", zap.Error(err))
+		}
+		b.Logger.Info("[USERS-API] Migraciones aplicadas", zap.Int("count", applied))
+	}
+	return b
+}
+
+func (b *AppBuilder) BuildRedisConnection() *AppBuilder {
+	if b.config.CacheDriver != config.CacheDriverRedis {
+		return b
+	}
+	b.redisClient = redis.ConnectRedis(b.config.RedisURI.Value())
 	if b.redisClient == nil {
 		b.Logger.Warn("[USERS-API] Redis no está disponible. La aplicación funcionará sin caché.")
 	}
 	return b
 }
 
+func (b *AppBuilder) BuildCache() *AppBuilder {
+	switch {
+	case b.config.CacheDriver == config.CacheDriverMemory:
+		b.cache = cache.NewMemoryCache()
+	case b.redisClient != nil:
+		b.cache = cache.NewRedisCache(b.redisClient)
+	default:
+		b.cache = cache.NewNoopCache()
+	}
+	b.Logger.Info("[USERS-API] Caché inicializada", zap.Bool("enabled", b.cache.Enabled()))
+	return b
+}
+
+// Close libera las conexiones a PostgreSQL y Redis y vacía el buffer del logger
+func (b *AppBuilder) Close() {
+	b.DisconnectDB()
+	b.DisconnectRedis()
+	if b.shutdownTracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), b.config.ShutdownTimeout)
+		defer cancel()
+		if err := b.shutdownTracing(ctx); err != nil {
+			b.Logger.Error("[USERS-API] Error al cerrar el exporter de trazas", zap.Error(err))
+		}
+	}
+	_ = b.Logger.Sync()
+}
+
+func (b *AppBuilder) DisconnectRedis() {
+	if b.redisClient == nil {
+		return
+	}
+	if err := b.redisClient.Close(); err != nil {
+		b.Logger.Error("[USERS-API] Error al desconectar de Redis", zap.Error(err))
+	} else {
+		b.Logger.Info("[USERS-API] Conexión a Redis cerrada")
+	}
+}
+
 func (b *AppBuilder) DisconnectDB() {
 	if b.db != nil {
 		sqlDB, err := b.db.DB()
@@ -73,38 +263,349 @@ func (b *AppBuilder) DisconnectDB() {
 			}
 		}
 	}
-	_ = b.Logger.Sync()
 }
 
 func (b *AppBuilder) BuildUserRepo() *AppBuilder {
-	b.userRepo = client.NewUserRepository(b.db, b.Logger)
-	b.Logger.Info("[USERS-API] Repositorio de usuarios inicializado")
+	if b.config.DBDriver == config.DBDriverMemory {
+		b.userRepo = client.NewMemoryUserRepository()
+		b.roleRepo = client.NewMemoryRoleRepository()
+		b.organizationRepo = client.NewMemoryOrganizationRepository()
+		b.groupRepo, b.membershipRepo = client.NewMemoryGroupRepositories(b.userRepo)
+		b.invitationRepo = client.NewMemoryInvitationRepository()
+	} else {
+		b.userRepo = client.NewUserRepository(b.db, b.Logger)
+		b.roleRepo = client.NewRoleRepository(b.db, b.Logger)
+		b.organizationRepo = client.NewOrganizationRepository(b.db, b.Logger)
+		b.groupRepo = client.NewGroupRepository(b.db, b.Logger)
+		b.membershipRepo = client.NewMembershipRepository(b.db, b.Logger)
+		b.invitationRepo = client.NewInvitationRepository(b.db, b.Logger)
+	}
+	b.Logger.Info("[USERS-API] Repositorios de usuarios, roles, organizaciones, grupos e invitaciones inicializados")
 	return b
 }
 
 func (b *AppBuilder) BuildUserService() *AppBuilder {
-	b.userService = services.NewUserService(b.userRepo, b.redisClient, b.Logger)
+	b.roleService = services.NewRoleService(b.roleRepo, b.cache, b.Logger)
+	b.Logger.Info("[USERS-API] Servicio de roles inicializado")
+	b.orgService = services.NewOrganizationService(b.organizationRepo, b.cache, b.Logger)
+	b.Logger.Info("[USERS-API] Servicio de organizaciones inicializado")
+	b.hasher = b.buildPasswordHasher()
+	b.userService = services.NewUserService(b.userRepo, b.roleService, b.hasher, b.buildPasswordPolicy(), b.config.PasswordHistory, b.cache, b.Logger)
 	b.Logger.Info("[USERS-API] Servicio de usuarios inicializado")
-	b.authService = services.NewAuthService(b.userRepo, b.redisClient, b.Logger)
+	b.groupService = services.NewGroupService(b.groupRepo, b.membershipRepo, b.userRepo, b.Logger)
+	b.Logger.Info("[USERS-API] Servicio de grupos inicializado")
+	b.invitations = services.NewInvitationService(b.invitationRepo, b.userRepo, b.userService, b.roleService,
+		b.buildNotifier(), b.config.JWTSecret.Value(), b.config.InvitationTTL, b.Logger)
+	b.Logger.Info("[USERS-API] Servicio de invitaciones inicializado")
+	b.authService = services.NewAuthService(b.userRepo, b.hasher, b.cache, b.Logger)
 	b.Logger.Info("[USERS-API] Servicio de autenticación inicializado")
+	b.cacheService = services.NewCacheService(b.userRepo, b.cache, b.Logger)
+	b.Logger.Info("[USERS-API] Servicio de caché inicializado")
+	b.healthService = services.NewHealthService(b.healthChecks(), b.Logger)
+	b.Logger.Info("[USERS-API] Servicio de salud inicializado")
+	b.tokenService = services.NewTokenService(b.config.JWTSecret.Value(), b.config.JWTTTL, b.membershipRepo, b.Logger)
+	if !b.tokenService.Enabled() {
+		b.Logger.Warn("[USERS-API] JWT_SECRET vacío, no se emiten ni aceptan tokens de acceso")
+	}
+	b.Logger.Info("[USERS-API] Servicio de tokens inicializado")
+	b.credentials = services.NewCredentialService(b.config.UsersAPIKey.Value(), b.tokenService, b.roleService, b.orgService, b.userRepo, b.cache, b.Logger)
 	return b
 }
 
 func (b *AppBuilder) BuildUserController() *AppBuilder {
 	b.userController = controllers.NewUserController(b.userService, b.Logger)
 	b.Logger.Info("[USERS-API] Controlador de usuarios inicializado")
-	b.authController = controllers.NewAuthController(b.authService, b.Logger)
+	b.authController = controllers.NewAuthController(b.authService, b.tokenService, b.Logger)
 	b.Logger.Info("[USERS-API] Controlador de autenticación inicializado")
+	b.cacheController = controllers.NewCacheController(b.cacheService, b.Logger)
+	b.Logger.Info("[USERS-API] Controlador de caché inicializado")
+	b.healthController = controllers.NewHealthController(b.healthService, b.Logger)
+	b.Logger.Info("[USERS-API] Controlador de salud inicializado")
+	b.roleController = controllers.NewRoleController(b.roleService, b.Logger)
+	b.Logger.Info("[USERS-API] Controlador de roles inicializado")
+	b.groupController = controllers.NewGroupController(b.groupService, b.Logger)
+	b.Logger.Info("[USERS-API] Controlador de grupos inicializado")
+	b.inviteController = controllers.NewInvitationController(b.invitations, b.Logger)
+	b.Logger.Info("[USERS-API] Controlador de invitaciones inicializado")
+	b.graphqlHandler = graph.NewHandler(graph.NewResolver(b.userService, b.Logger), graph.Limits{
+		MaxDepth:      b.config.GraphQLMaxDepth,
+		MaxComplexity: b.config.GraphQLMaxCost,
+	})
+	b.Logger.Info("[USERS-API] Handler GraphQL inicializado")
 	return b
 }
 
 func (b *AppBuilder) BuildRouter() *AppBuilder {
-	b.router = gin.Default()
-	router.SetupRoutes(b.router, b.userController, b.authController)
+	b.router = gin.New()
+	b.router.Use(gin.Recovery())
+	b.router.Use(otelgin.Middleware(tracing.ServiceName))
+	b.router.Use(middlewares.RequestIDMiddleware(b.Logger))
+	b.router.Use(middlewares.LoggerMiddleware(b.Logger))
+	b.router.Use(middlewares.MetricsMiddleware())
+	router.SetupRoutes(b.router, b.credentials, &router.Controllers{
+		User:       b.userController,
+		Auth:       b.authController,
+		Cache:      b.cacheController,
+		Health:     b.healthController,
+		Role:       b.roleController,
+		Group:      b.groupController,
+		Invitation: b.inviteController,
+		GraphQL:    b.graphqlHandler,
+	})
 	b.Logger.Info("[USERS-API] Rutas configuradas")
 	return b
 }
 
+// buildPasswordHasher arma el hasher de PASSWORD_HASHER; los hashes de los demás se siguen aceptando
+func (b *AppBuilder) buildPasswordHasher() utils.PasswordHasher {
+	var hasher utils.PasswordHasher
+	var err error
+	if b.config.PasswordHasher == config.HasherBcrypt {
+		hasher, err = utils.NewBcryptHasher(b.config.BcryptCost)
+	} else {
+		params := utils.DefaultArgon2Params
+		params.Memory = uint32(b.config.Argon2Memory)
+		params.Iterations = uint32(b.config.Argon2Iterations)
+		params.Parallelism = uint8(b.config.Argon2Parallelism)
+		hasher, err = utils.NewArgon2idHasher(params)
+	}
+	if err != nil {
+		b.Logger.Fatal("[USERS-API] Error al configurar el hash de contraseñas", zap.Error(err))
+	}
+	b.Logger.Info("[USERS-API] Hash de contraseñas configurado", zap.String("algorithm", b.config.PasswordHasher))
+	return hasher
+}
+
+// buildPasswordPolicy arma la política de contraseñas con la lista de filtraciones incluida o la de
+// PASSWORD_BREACHED_FILE
+func (b *AppBuilder) buildPasswordPolicy() services.PasswordPolicy {
+	rules := services.PasswordRules{
+		MinLength:       b.config.PasswordMinLength,
+		MaxLength:       b.config.PasswordMaxLength,
+		RequiredClasses: b.config.PasswordClasses,
+	}
+	if !b.config.PasswordBreached {
+		b.Logger.Warn("[USERS-API] Chequeo de contraseñas filtradas deshabilitado")
+		return services.NewPasswordPolicy(rules, nil, b.Logger)
+	}
+
+	var breached utils.BreachedPasswords
+	var err error
+	if b.config.PasswordBreachFile != "" {
+		breached, err = utils.LoadBreachedPasswords(b.config.PasswordBreachFile)
+	} else {
+		breached, err = utils.NewBundledBreachedPasswords()
+	}
+	if err != nil {
+		b.Logger.Fatal("[USERS-API] Error al cargar la lista de contraseñas filtradas", zap.Error(err))
+	}
+	b.Logger.Info("[USERS-API] Política de contraseñas configurada")
+	return services.NewPasswordPolicy(rules, breached, b.Logger)
+}
+
+// buildNotifier elige cómo se entregan los tokens de invitación según INVITATION_NOTIFIER
+func (b *AppBuilder) buildNotifier() services.Notifier {
+	if b.config.InvitationNotifier == config.NotifierWebhook {
+		return services.NewWebhookNotifier(b.config.InvitationWebhook.Value(), b.Logger)
+	}
+	b.Logger.Warn("[USERS-API] Los tokens de invitación se escriben en el log, usar INVITATION_NOTIFIER=webhook en producción")
+	return services.NewLogNotifier(b.Logger)
+}
+
+// healthChecks arma las verificaciones de /readyz. Redis no es crítico porque la caché es opcional
+func (b *AppBuilder) healthChecks() []services.HealthCheck {
+	checks := []services.HealthCheck{
+		{
+			Name:     b.config.DBDriver,
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				if b.db == nil {
+					return nil
+				}
+				sqlDB, err := b.db.DB()
+				if err != nil {
+					return err
+				}
+				return sqlDB.PingContext(ctx)
+			},
+		},
+	}
+
+	if b.config.CacheDriver == config.CacheDriverRedis {
+		checks = append(checks, services.HealthCheck{
+			Name:     "redis",
+			Critical: false,
+			Check: func(ctx context.Context) error {
+				if b.redisClient == nil {
+					return errors.New("redis no está disponible")
+				}
+				return b.redisClient.Ping(ctx).Err()
+			},
+		})
+	}
+
+	if b.migrator != nil {
+		checks = append(checks, services.HealthCheck{
+			Name:     "migrations",
+			Critical: true,
+			Check: func(ctx context.Context) error {
+				current, err := b.migrator.CurrentVersion(ctx)
+				if err != nil {
+					return err
+				}
+				if latest := b.migrator.LatestVersion(); current < latest {
+					return fmt.Errorf("esquema desactualizado: versión %d, se espera %d", current, latest)
+				}
+				return nil
+			},
+		})
+	}
+
+	return checks
+}
+
+func (b *AppBuilder) BuildServer() *AppBuilder {
+	b.server = &http.Server{
+		Addr:         ":" + b.config.Port,
+		Handler:      b.router,
+		ReadTimeout:  b.config.ReadTimeout,
+		WriteTimeout: b.config.WriteTimeout,
+		IdleTimeout:  b.config.IdleTimeout,
+	}
+	b.Logger.Info("[USERS-API] Servidor HTTP configurado", zap.String("addr", b.server.Addr))
+	return b
+}
+
+// BuildGRPCServer arma el servidor gRPC sobre los mismos servicios que la API REST, salvo que GRPC_PORT esté vacío
+func (b *AppBuilder) BuildGRPCServer() *AppBuilder {
+	if b.config.GRPCPort == "" {
+		b.Logger.Info("[USERS-API] Servidor gRPC deshabilitado")
+		return b
+	}
+	users := grpcserver.NewUsersServer(b.userService, b.authService, b.tokenService, b.Logger)
+	b.grpcServer = grpcserver.NewServer(b.credentials, b.healthService, users, b.Logger)
+	b.Logger.Info("[USERS-API] Servidor gRPC configurado", zap.String("addr", ":"+b.config.GRPCPort))
+	return b
+}
+
+// Run atiende solicitudes HTTP y gRPC hasta que se cancele ctx, luego deja de aceptar conexiones,
+// espera las solicitudes en curso hasta SHUTDOWN_TIMEOUT y cierra las dependencias
+func (b *AppBuilder) Run(ctx context.Context) error {
+	defer b.Close()
+
+	// El puerto gRPC se abre antes de arrancar HTTP, así un error al escuchar no deja el servidor
+	// HTTP corriendo sin apagar
+	var listener net.Listener
+	if b.grpcServer != nil {
+		var err error
+		listener, err = net.Listen("tcp", ":"+b.config.GRPCPort)
+		if err != nil {
+			return fmt.Errorf("no se pudo escuchar en GRPC_PORT %s: %w", b.config.GRPCPort, err)
+		}
+	}
+
+	serverErr := make(chan error, 2)
+	go func() {
+		b.Logger.Info("[USERS-API] Iniciando servidor", zap.String("addr", b.server.Addr))
+		if err := b.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	if listener != nil {
+		go func() {
+			b.Logger.Info("[USERS-API] Iniciando servidor gRPC", zap.String("addr", listener.Addr().String()))
+			if err := b.grpcServer.Serve(listener); err != nil {
+				serverErr <- err
+			}
+		}()
+	}
+
+	// Si uno de los servidores falla se apaga también el otro, igual que al recibir la señal
+	var serveErr error
+	select {
+	case serveErr = <-serverErr:
+		b.Logger.Error("[USERS-API] Error en un servidor, apagando el resto", zap.Error(serveErr))
+	case <-ctx.Done():
+		b.Logger.Info("[USERS-API] Señal de apagado recibida, esperando solicitudes en curso",
+			zap.Duration("timeout", b.config.ShutdownTimeout))
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), b.config.ShutdownTimeout)
+	defer cancel()
+
+	if b.grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			b.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		defer func() {
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				b.Logger.Warn("[USERS-API] Timeout esperando llamadas gRPC en curso, se cancelan")
+				b.grpcServer.Stop()
+			}
+		}()
+	}
+
+	if err := b.server.Shutdown(shutdownCtx); err != nil {
+		b.Logger.Error("[USERS-API] Error al apagar el servidor", zap.Error(err))
+		return errors.Join(serveErr, err)
+	}
+	if serveErr != nil {
+		return serveErr
+	}
+
+	b.Logger.Info("[USERS-API] Servidor apagado correctamente")
+	return nil
+}
+
+// WarmUpCache precarga la caché de cada organización en segundo plano para no demorar el arranque del servidor
+func (b *AppBuilder) WarmUpCache(limit int) {
+	go func() {
+		organizations, err := b.organizationRepo.ReadAll(context.Background())
+		if err != nil {
+			b.Logger.Warn("[USERS-API] No se pudo precargar la caché", zap.Error(err))
+			return
+		}
+		for _, organization := range organizations {
+			ctx := auth.WithPrincipal(context.Background(), auth.System(organization.ID))
+			if _, err := b.cacheService.WarmUp(ctx, limit); err != nil {
+				b.Logger.Warn("[USERS-API] No se pudo precargar la caché",
+					zap.String("tenant_id", organization.ID), zap.Error(err))
+				// Un error en una organización no impide precargar las demás
+				continue
+			}
+		}
+	}()
+}
+
+func (b *AppBuilder) GetUserService() services.UserService {
+	return b.userService
+}
+
+func (b *AppBuilder) GetUserRepo() client.UserRepository {
+	return b.userRepo
+}
+
+func (b *AppBuilder) GetOrganizationService() services.OrganizationService {
+	return b.orgService
+}
+
+func (b *AppBuilder) GetMigrator() *migrations.Migrator {
+	return b.migrator
+}
+
+func (b *AppBuilder) GetConfig() *config.Config {
+	return b.config
+}
+
 func (b *AppBuilder) GetRouter() *gin.Engine {
 	return b.router
 }
+
+func (b *AppBuilder) GetGRPCServer() *grpc.Server {
+	return b.grpcServer
+}
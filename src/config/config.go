@@ -0,0 +1,501 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"users-api/src/utils"
+
+	"github.com/joho/godotenv"
+)
+
+// DefaultFile es el archivo de configuración que se lee si existe
+const DefaultFile = ".env"
+
+// Secret es un valor que nunca se imprime en logs ni en JSON
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "[REDACTED]"
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(s.String())), nil
+}
+
+// Value devuelve el valor real del secreto
+func (s Secret) Value() string {
+	return string(s)
+}
+
+const (
+	DBDriverPostgres = "postgres"
+	DBDriverSQLite   = "sqlite"
+	DBDriverMemory   = "memory"
+)
+
+const (
+	CacheDriverRedis  = "redis"
+	CacheDriverMemory = "memory"
+	CacheDriverNone   = "none"
+)
+
+const (
+	NotifierLog     = "log"
+	NotifierWebhook = "webhook"
+)
+
+const (
+	HasherArgon2id = "argon2id"
+	HasherBcrypt   = "bcrypt"
+)
+
+// Clases de caracteres que PASSWORD_REQUIRE_CLASSES puede exigir
+const (
+	PasswordClassLower  = "lower"
+	PasswordClassUpper  = "upper"
+	PasswordClassDigit  = "digit"
+	PasswordClassSymbol = "symbol"
+)
+
+type Config struct {
+	Port               string
+	GRPCPort           string
+	DBDriver           string
+	PostgresURI        Secret
+	SQLitePath         string
+	UsersAPIKey        Secret
+	JWTSecret          Secret
+	JWTTTL             time.Duration
+	RedisURI           Secret
+	CacheDriver        string
+	CacheWarmUpOnStart bool
+	CacheWarmUpLimit   int
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	IdleTimeout        time.Duration
+	ShutdownTimeout    time.Duration
+	TracingExporter    string
+	TracingFile        string
+	TracingEndpoint    string
+	TracingSampleRatio float64
+	LogMode            string
+	LogLevel           string
+	LogSampling        bool
+	LogSamplingInitial int
+	LogSamplingAfter   int
+	MigrateOnStart     bool
+	GraphQLMaxDepth    int
+	GraphQLMaxCost     int
+	InvitationTTL      time.Duration
+	InvitationNotifier string
+	InvitationWebhook  Secret
+	PasswordHasher     string
+	BcryptCost         int
+	Argon2Memory       int
+	Argon2Iterations   int
+	Argon2Parallelism  int
+	PasswordMinLength  int
+	PasswordMaxLength  int
+	PasswordClasses    []string
+	PasswordBreached   bool
+	PasswordBreachFile string
+	PasswordHistory    int
+}
+
+// Default devuelve la configuración con los valores por defecto
+func Default() *Config {
+	return &Config{
+		Port:               "8080",
+		GRPCPort:           "9090",
+		DBDriver:           DBDriverPostgres,
+		SQLitePath:         "users.db",
+		JWTTTL:             time.Hour,
+		CacheDriver:        CacheDriverRedis,
+		CacheWarmUpLimit:   100,
+		ReadTimeout:        15 * time.Second,
+		WriteTimeout:       15 * time.Second,
+		IdleTimeout:        60 * time.Second,
+		ShutdownTimeout:    20 * time.Second,
+		TracingExporter:    "none",
+		TracingFile:        "traces.json",
+		TracingSampleRatio: 1,
+		LogMode:            "development",
+		LogLevel:           "info",
+		LogSamplingInitial: 100,
+		LogSamplingAfter:   100,
+		GraphQLMaxDepth:    8,
+		GraphQLMaxCost:     1000,
+		InvitationTTL:      72 * time.Hour,
+		InvitationNotifier: NotifierLog,
+		PasswordHasher:     HasherArgon2id,
+		BcryptCost:         10,
+		Argon2Memory:       19 * 1024,
+		Argon2Iterations:   2,
+		Argon2Parallelism:  1,
+		PasswordMinLength:  8,
+		PasswordMaxLength:  128,
+		PasswordBreached:   true,
+		PasswordHistory:    5,
+	}
+}
+
+// value es un valor de configuración que se puede asignar desde texto, igual que flag.Value
+type value interface {
+	String() string
+	Set(string) error
+}
+
+type field struct {
+	key   string
+	usage string
+	value value
+}
+
+func (c *Config) fields() []field {
+	return []field{
+		{"PORT", "Puerto del servidor HTTP", (*stringValue)(&c.Port)},
+		{"GRPC_PORT", "Puerto del servidor gRPC, vacío para deshabilitarlo", (*stringValue)(&c.GRPCPort)},
+		{"DB_DRIVER", "Base de datos: postgres, sqlite o memory (estas dos para tests y desarrollo local)", (*stringValue)(&c.DBDriver)},
+		{"POSTGRES_URI", "URI de conexión a PostgreSQL", (*secretValue)(&c.PostgresURI)},
+		{"SQLITE_PATH", "Archivo SQLite, o :memory: para una base temporal", (*stringValue)(&c.SQLitePath)},
+		{"USERS_API_KEY", "API key requerida en el header Authorization", (*secretValue)(&c.UsersAPIKey)},
+		{"JWT_SECRET", "Clave HMAC para firmar los tokens de acceso, vacía para no emitirlos ni aceptarlos", (*secretValue)(&c.JWTSecret)},
+		{"JWT_TTL", "Duración de los tokens de acceso", (*durationValue)(&c.JWTTTL)},
+		{"REDIS_URI", "URI de conexión a Redis, vacía para funcionar sin caché", (*secretValue)(&c.RedisURI)},
+		{"CACHE_DRIVER", "Caché: redis, memory (en proceso, para tests y desarrollo local) o none", (*stringValue)(&c.CacheDriver)},
+		{"CACHE_WARMUP_ON_START", "Precargar la caché al iniciar", (*boolValue)(&c.CacheWarmUpOnStart)},
+		{"CACHE_WARMUP_LIMIT", "Cantidad de usuarios a precargar en caché", (*intValue)(&c.CacheWarmUpLimit)},
+		{"SERVER_READ_TIMEOUT", "Tiempo máximo para leer una solicitud", (*durationValue)(&c.ReadTimeout)},
+		{"SERVER_WRITE_TIMEOUT", "Tiempo máximo para escribir una respuesta", (*durationValue)(&c.WriteTimeout)},
+		{"SERVER_IDLE_TIMEOUT", "Tiempo máximo de una conexión keep-alive inactiva", (*durationValue)(&c.IdleTimeout)},
+		{"SHUTDOWN_TIMEOUT", "Tiempo máximo para terminar las solicitudes en curso al apagar", (*durationValue)(&c.ShutdownTimeout)},
+		{"TRACING_EXPORTER", "Exporter de trazas: none, stdout, file u otlp", (*stringValue)(&c.TracingExporter)},
+		{"TRACING_FILE", "Archivo donde se escriben las trazas con el exporter file", (*stringValue)(&c.TracingFile)},
+		{"TRACING_ENDPOINT", "URL del colector OTLP/HTTP, vacía para usar OTEL_EXPORTER_OTLP_ENDPOINT", (*stringValue)(&c.TracingEndpoint)},
+		{"TRACING_SAMPLE_RATIO", "Proporción de trazas muestreadas, entre 0 y 1", (*floatValue)(&c.TracingSampleRatio)},
+		{"LOG_MODE", "Formato de logs: development (consola) o production (JSON)", (*stringValue)(&c.LogMode)},
+		{"LOG_LEVEL", "Nivel mínimo de log: debug, info, warn o error", (*stringValue)(&c.LogLevel)},
+		{"LOG_SAMPLING", "Muestrear logs repetidos para limitar el volumen", (*boolValue)(&c.LogSampling)},
+		{"LOG_SAMPLING_INITIAL", "Logs iguales por segundo que se escriben antes de muestrear", (*intValue)(&c.LogSamplingInitial)},
+		{"LOG_SAMPLING_THEREAFTER", "Luego del inicial, se escribe uno de cada N logs iguales", (*intValue)(&c.LogSamplingAfter)},
+		{"MIGRATE_ON_START", "Aplicar las migraciones pendientes al iniciar", (*boolValue)(&c.MigrateOnStart)},
+		{"GRAPHQL_MAX_DEPTH", "Niveles máximos de selección anidados en una consulta GraphQL", (*intValue)(&c.GraphQLMaxDepth)},
+		{"GRAPHQL_MAX_COMPLEXITY", "Complejidad máxima estimada de una consulta GraphQL", (*intValue)(&c.GraphQLMaxCost)},
+		{"INVITATION_TTL", "Vencimiento de las invitaciones que no indican uno, hasta 720h", (*durationValue)(&c.InvitationTTL)},
+		{"INVITATION_NOTIFIER", "Cómo se entregan los tokens de invitación: log (solo desarrollo) o webhook", (*stringValue)(&c.InvitationNotifier)},
+		{"INVITATION_WEBHOOK_URL", "URL que recibe por POST cada invitación con el notificador webhook", (*secretValue)(&c.InvitationWebhook)},
+		{"PASSWORD_HASHER", "Algoritmo de las contraseñas nuevas: argon2id o bcrypt. Las demás se rehashean al iniciar sesión", (*stringValue)(&c.PasswordHasher)},
+		{"BCRYPT_COST", "Costo de bcrypt, entre 4 y 31", (*intValue)(&c.BcryptCost)},
+		{"ARGON2_MEMORY", "Memoria de argon2id en KiB", (*intValue)(&c.Argon2Memory)},
+		{"ARGON2_ITERATIONS", "Iteraciones de argon2id", (*intValue)(&c.Argon2Iterations)},
+		{"ARGON2_PARALLELISM", "Hilos de argon2id, entre 1 y 255", (*intValue)(&c.Argon2Parallelism)},
+		{"PASSWORD_MIN_LENGTH", "Caracteres mínimos de una contraseña", (*intValue)(&c.PasswordMinLength)},
+		{"PASSWORD_MAX_LENGTH", "Caracteres máximos de una contraseña", (*intValue)(&c.PasswordMaxLength)},
+		{"PASSWORD_REQUIRE_CLASSES", "Clases de caracteres obligatorias separadas por comas: lower, upper, digit, symbol", (*listValue)(&c.PasswordClasses)},
+		{"PASSWORD_BREACHED_CHECK", "Rechazar contraseñas que aparecen en filtraciones conocidas", (*boolValue)(&c.PasswordBreached)},
+		{"PASSWORD_BREACHED_FILE", "Archivo de SHA-1 de contraseñas filtradas, vacío para usar la lista incluida", (*stringValue)(&c.PasswordBreachFile)},
+		{"PASSWORD_HISTORY", "Últimas contraseñas, incluida la actual, que no se pueden reutilizar; 0 para no controlarlo", (*intValue)(&c.PasswordHistory)},
+	}
+}
+
+// Load arma la configuración en orden: valores por defecto, archivo opcional,
+// variables de entorno y flags. Devuelve los argumentos que no son flags.
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+	fields := cfg.fields()
+
+	fs := flag.NewFlagSet("users-api", flag.ContinueOnError)
+	configFile := fs.String("config", "", "Archivo de configuración (por defecto "+DefaultFile+" si existe)")
+	flagValues := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		flagValues[f.key] = fs.String(flagName(f.key), "", f.usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	values := map[string]string{}
+
+	path, required := *configFile, true
+	if path == "" {
+		path, required = os.Getenv("CONFIG_FILE"), true
+	}
+	if path == "" {
+		path, required = DefaultFile, false
+	}
+	fileValues, err := godotenv.Read(path)
+	if err != nil && (required || !errors.Is(err, os.ErrNotExist)) {
+		return nil, nil, fmt.Errorf("error al leer el archivo de configuración %s: %w", path, err)
+	}
+	for key, value := range fileValues {
+		values[key] = value
+	}
+
+	for _, f := range fields {
+		if value, ok := os.LookupEnv(f.key); ok {
+			values[f.key] = value
+		}
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if flagName(f.key) == fl.Name {
+				values[f.key] = *flagValues[f.key]
+			}
+		}
+	})
+
+	var errs []error
+	for _, f := range fields {
+		value, ok := values[f.key]
+		if !ok {
+			continue
+		}
+		if err := f.value.Set(strings.TrimSpace(value)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.key, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, fs.Args(), nil
+}
+
+// Validate verifica que estén las claves obligatorias y que los valores sean coherentes
+func (c *Config) Validate() error {
+	var errs []error
+	switch c.DBDriver {
+	case DBDriverPostgres:
+		if c.PostgresURI == "" {
+			errs = append(errs, errors.New("POSTGRES_URI es obligatoria"))
+		}
+	case DBDriverSQLite:
+		if c.SQLitePath == "" {
+			errs = append(errs, errors.New("SQLITE_PATH es obligatorio con DB_DRIVER=sqlite"))
+		}
+	case DBDriverMemory:
+	default:
+		errs = append(errs, fmt.Errorf("DB_DRIVER inválido %q", c.DBDriver))
+	}
+	if c.UsersAPIKey == "" {
+		errs = append(errs, errors.New("USERS_API_KEY es obligatoria"))
+	}
+	if c.Port == "" {
+		errs = append(errs, errors.New("PORT no puede estar vacío"))
+	}
+	if c.GRPCPort != "" && c.GRPCPort == c.Port {
+		errs = append(errs, errors.New("GRPC_PORT debe ser distinto de PORT"))
+	}
+	if c.JWTSecret != "" && len(c.JWTSecret) < 32 {
+		errs = append(errs, errors.New("JWT_SECRET debe tener al menos 32 caracteres"))
+	}
+	if c.GraphQLMaxDepth <= 0 || c.GraphQLMaxCost <= 0 {
+		errs = append(errs, errors.New("GRAPHQL_MAX_DEPTH y GRAPHQL_MAX_COMPLEXITY deben ser mayores a 0"))
+	}
+	if c.InvitationTTL <= 0 || c.InvitationTTL > 30*24*time.Hour {
+		errs = append(errs, errors.New("INVITATION_TTL debe ser mayor a 0 y no superar 720h"))
+	}
+	switch c.InvitationNotifier {
+	case NotifierLog:
+		if c.LogMode == "production" {
+			errs = append(errs, errors.New("INVITATION_NOTIFIER=log deja los tokens en el log, no se permite con LOG_MODE=production"))
+		}
+	case NotifierWebhook:
+		if c.InvitationWebhook == "" {
+			errs = append(errs, errors.New("INVITATION_WEBHOOK_URL es obligatoria con INVITATION_NOTIFIER=webhook"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("INVITATION_NOTIFIER inválido %q", c.InvitationNotifier))
+	}
+	switch c.PasswordHasher {
+	case HasherArgon2id, HasherBcrypt:
+	default:
+		errs = append(errs, fmt.Errorf("PASSWORD_HASHER inválido %q", c.PasswordHasher))
+	}
+	if c.BcryptCost < 4 || c.BcryptCost > 31 {
+		errs = append(errs, errors.New("BCRYPT_COST debe estar entre 4 y 31"))
+	}
+	if c.Argon2Iterations < 1 || c.Argon2Parallelism < 1 || c.Argon2Parallelism > 255 || c.Argon2Memory < 8*c.Argon2Parallelism {
+		errs = append(errs, errors.New("ARGON2_ITERATIONS debe ser mayor a 0, ARGON2_PARALLELISM estar entre 1 y 255 y ARGON2_MEMORY ser al menos 8 KiB por hilo"))
+	}
+	if c.PasswordMinLength < 1 || c.PasswordMaxLength < c.PasswordMinLength {
+		errs = append(errs, errors.New("PASSWORD_MIN_LENGTH debe ser mayor a 0 y PASSWORD_MAX_LENGTH no puede ser menor"))
+	}
+	// bcrypt rechaza más de 72 bytes, así que un máximo mayor aceptaría contraseñas que después no se pueden hashear
+	if c.PasswordHasher == HasherBcrypt && c.PasswordMaxLength > utils.BcryptMaxPasswordBytes {
+		errs = append(errs, fmt.Errorf("PASSWORD_MAX_LENGTH no puede superar %d con PASSWORD_HASHER=bcrypt", utils.BcryptMaxPasswordBytes))
+	}
+	if c.PasswordHistory < 0 {
+		errs = append(errs, errors.New("PASSWORD_HISTORY no puede ser negativo"))
+	}
+	for _, class := range c.PasswordClasses {
+		switch class {
+		case PasswordClassLower, PasswordClassUpper, PasswordClassDigit, PasswordClassSymbol:
+		default:
+			errs = append(errs, fmt.Errorf("PASSWORD_REQUIRE_CLASSES: clase inválida %q", class))
+		}
+	}
+	if c.JWTTTL <= 0 {
+		errs = append(errs, errors.New("JWT_TTL debe ser mayor a 0"))
+	}
+	switch c.CacheDriver {
+	case CacheDriverRedis, CacheDriverMemory, CacheDriverNone:
+	default:
+		errs = append(errs, fmt.Errorf("CACHE_DRIVER inválido %q", c.CacheDriver))
+	}
+	if c.CacheWarmUpLimit <= 0 {
+		errs = append(errs, errors.New("CACHE_WARMUP_LIMIT debe ser mayor a 0"))
+	}
+	if c.ReadTimeout <= 0 || c.WriteTimeout <= 0 || c.IdleTimeout <= 0 {
+		errs = append(errs, errors.New("los timeouts del servidor deben ser mayores a 0"))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT debe ser mayor a 0"))
+	}
+	switch c.TracingExporter {
+	case "none", "stdout", "otlp":
+	case "file":
+		if c.TracingFile == "" {
+			errs = append(errs, errors.New("TRACING_FILE es obligatorio con el exporter file"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER inválido %q", c.TracingExporter))
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_RATIO debe estar entre 0 y 1"))
+	}
+	if c.LogMode != "development" && c.LogMode != "production" {
+		errs = append(errs, fmt.Errorf("LOG_MODE inválido %q", c.LogMode))
+	}
+	if c.LogSampling && (c.LogSamplingInitial <= 0 || c.LogSamplingAfter <= 0) {
+		errs = append(errs, errors.New("LOG_SAMPLING_INITIAL y LOG_SAMPLING_THEREAFTER deben ser mayores a 0"))
+	}
+	return errors.Join(errs...)
+}
+
+// String imprime la configuración con los secretos ocultos
+func (c Config) String() string {
+	var b strings.Builder
+	for i, f := range c.fields() {
+		if i > 0 {
+			b.WriteString(" ")
+		}
+		fmt.Fprintf(&b, "%s=%s", f.key, f.value.String())
+	}
+	return b.String()
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+type stringValue string
+
+func (v *stringValue) String() string { return string(*v) }
+
+func (v *stringValue) Set(s string) error {
+	*v = stringValue(s)
+	return nil
+}
+
+type secretValue Secret
+
+func (v *secretValue) String() string { return Secret(*v).String() }
+
+func (v *secretValue) Set(s string) error {
+	*v = secretValue(s)
+	return nil
+}
+
+type boolValue bool
+
+func (v *boolValue) String() string { return strconv.FormatBool(bool(*v)) }
+
+func (v *boolValue) Set(s string) error {
+	if s == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("valor booleano inválido %q", s)
+	}
+	*v = boolValue(parsed)
+	return nil
+}
+
+type intValue int
+
+func (v *intValue) String() string { return strconv.Itoa(int(*v)) }
+
+func (v *intValue) Set(s string) error {
+	if s == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("valor entero inválido %q", s)
+	}
+	*v = intValue(parsed)
+	return nil
+}
+
+// listValue es una lista separada por comas; los elementos vacíos se descartan
+type listValue []string
+
+func (v *listValue) String() string { return strings.Join(*v, ",") }
+
+func (v *listValue) Set(s string) error {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*v = items
+	return nil
+}
+
+type floatValue float64
+
+func (v *floatValue) String() string { return strconv.FormatFloat(float64(*v), 'g', -1, 64) }
+
+func (v *floatValue) Set(s string) error {
+	if s == "" {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("valor numérico inválido %q", s)
+	}
+	*v = floatValue(parsed)
+	return nil
+}
+
+type durationValue time.Duration
+
+func (v *durationValue) String() string { return time.Duration(*v).String() }
+
+func (v *durationValue) Set(s string) error {
+	if s == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("duración inválida %q", s)
+	}
+	*v = durationValue(parsed)
+	return nil
+}
@@ -2,7 +2,6 @@ package db
 
 import (
 	"sync"
-	"users-api/src/config/envs"
 
 	"go.uber.org/zap"
 
@@ -13,12 +12,11 @@ import (
 var once sync.Once
 var dbInstance *gorm.DB
 
-func ConnectDB(logger *zap.Logger) (*gorm.DB, error) {
+func ConnectDB(uri string, logger *zap.Logger) (*gorm.DB, error) {
 	var err error
-	POSTGRES_URI := envs.LoadEnvs(".env").Get("POSTGRES_URI")
 
 	once.Do(func() {
-		dbInstance, err = gorm.Open(postgres.Open(POSTGRES_URI), &gorm.Config{})
+		dbInstance, err = gorm.Open(postgres.Open(uri), &gorm.Config{})
 		if err != nil {
 			logger.Fatal("[USERS-API] Error al conectar con PostgreSQL", zap.Error(err))
 		}
@@ -32,14 +30,6 @@ func ConnectDB(logger *zap.Logger) (*gorm.DB, error) {
 			logger.Warn("[USERS-API] Error al habilitar la extensión uuid-ossp", zap.Error(err))
 		}
 
-		// Si deseas realizar migraciones automáticas
-		/*
-			err = dbInstance.AutoMigrate(&models.Inscripto{})
-			if err != nil {
-				logger.Fatal("[INSCRIPTION-API] Error al realizar la migración automática", zap.Error(err))
-			}
-		*/
-
 		logger.Info("[USERS-API] Conexión a PostgreSQL establecida")
 	})
 
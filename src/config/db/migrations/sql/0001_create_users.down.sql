@@ -0,0 +1,4 @@
+-- No hace nada a propósito: la versión 1 adopta la tabla users que las bases existentes ya tenían
+-- creada a mano, así que revertirla no debe borrar esos datos. Para eliminar la tabla hay que
+-- hacerlo manualmente después de migrate down hasta la versión 0.
+SELECT 1;
@@ -0,0 +1,18 @@
+-- Tabla base de usuarios. Usa IF NOT EXISTS porque las bases existentes ya tienen
+-- el esquema creado a mano con la misma estructura.
+CREATE TABLE IF NOT EXISTS users (
+    id          text        PRIMARY KEY,
+    name        text        NOT NULL,
+    lastname    text        NOT NULL,
+    birthdate   timestamptz NOT NULL,
+    role        text        NOT NULL,
+    email       text        NOT NULL,
+    password    text        NOT NULL,
+    avatar      text,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
@@ -0,0 +1,2 @@
+-- Usado por la precarga de caché para buscar los usuarios con actividad reciente
+CREATE INDEX IF NOT EXISTS idx_users_updated_at ON users (updated_at DESC);
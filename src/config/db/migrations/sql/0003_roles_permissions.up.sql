@@ -0,0 +1,44 @@
+-- Roles y permisos. Los datos iniciales deben coincidir con auth.DefaultRoles y auth.Permissions.
+CREATE TABLE roles (
+    name         text PRIMARY KEY,
+    description  text NOT NULL
+);
+
+CREATE TABLE permissions (
+    name         text PRIMARY KEY,
+    description  text NOT NULL
+);
+
+CREATE TABLE role_permissions (
+    role_name        text NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+    permission_name  text NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role_name, permission_name)
+);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users.read.any',   'Consultar cualquier usuario'),
+    ('users.read.self',  'Consultar la propia cuenta'),
+    ('users.write.any',  'Crear y modificar cualquier usuario'),
+    ('users.write.self', 'Modificar y eliminar la propia cuenta'),
+    ('users.delete.any', 'Eliminar cualquier usuario'),
+    ('roles.assign',     'Asignar o cambiar el rol de un usuario'),
+    ('cache.manage',     'Administrar la caché');
+
+INSERT INTO roles (name, description) VALUES
+    ('user',  'Usuario final, solo opera sobre su cuenta'),
+    ('admin', 'Administrador con todos los permisos');
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('user', 'users.read.self'),
+    ('user', 'users.write.self');
+
+INSERT INTO role_permissions (role_name, permission_name)
+SELECT 'admin', name FROM permissions;
+
+-- Los roles libres que ya tengan los usuarios se conservan sin permisos, para poder
+-- referenciarlos desde users.role. Un administrador les asigna permisos si corresponde
+INSERT INTO roles (name, description)
+SELECT DISTINCT role, 'Rol existente antes de introducir permisos' FROM users
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles (name);
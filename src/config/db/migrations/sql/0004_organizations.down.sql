@@ -0,0 +1,6 @@
+-- Falla si el mismo email quedó registrado en más de una organización
+DROP INDEX IF EXISTS idx_users_tenant_email;
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+ALTER TABLE users DROP COLUMN IF EXISTS tenant_id;
+DROP TABLE IF EXISTS organizations;
+DELETE FROM permissions WHERE name = 'organizations.manage';
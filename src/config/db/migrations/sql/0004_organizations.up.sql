@@ -0,0 +1,22 @@
+-- Organizaciones (tenants). Los usuarios existentes quedan en la organización default, que es
+-- la que corresponde a USERS_API_KEY. Las demás se autentican con su propia API key, de la que
+-- solo se guarda el SHA-256.
+CREATE TABLE organizations (
+    id            text        PRIMARY KEY,
+    name          text        NOT NULL,
+    api_key_hash  text        UNIQUE,
+    created_at    timestamptz,
+    updated_at    timestamptz
+);
+
+INSERT INTO organizations (id, name, created_at, updated_at) VALUES ('default', 'Default', now(), now());
+
+ALTER TABLE users ADD COLUMN tenant_id text NOT NULL DEFAULT 'default' REFERENCES organizations (id);
+
+-- El mismo email puede existir en distintas organizaciones
+DROP INDEX IF EXISTS idx_users_email;
+CREATE UNIQUE INDEX idx_users_tenant_email ON users (tenant_id, email);
+
+-- Ningún rol lo recibe, las organizaciones se administran desde la CLI
+INSERT INTO permissions (name, description) VALUES
+    ('organizations.manage', 'Crear organizaciones y rotar sus API keys');
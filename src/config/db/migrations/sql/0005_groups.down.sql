@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE name IN ('groups.read', 'groups.manage');
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,30 @@
+-- Grupos de usuarios y sus miembros. La organización se repite en group_members para que las
+-- consultas de pertenencia se filtren sin pasar por groups.
+CREATE TABLE groups (
+    id           text        PRIMARY KEY,
+    tenant_id    text        NOT NULL REFERENCES organizations (id),
+    name         text        NOT NULL,
+    description  text        NOT NULL DEFAULT '',
+    created_at   timestamptz,
+    updated_at   timestamptz
+);
+
+CREATE UNIQUE INDEX idx_groups_tenant_name ON groups (tenant_id, name);
+
+CREATE TABLE group_members (
+    group_id    text        NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id     text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    tenant_id   text        NOT NULL REFERENCES organizations (id),
+    created_at  timestamptz,
+    PRIMARY KEY (group_id, user_id)
+);
+
+CREATE INDEX idx_group_members_user_id ON group_members (user_id);
+
+INSERT INTO permissions (name, description) VALUES
+    ('groups.read',   'Consultar grupos y sus miembros'),
+    ('groups.manage', 'Crear, modificar y eliminar grupos y sus miembros');
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('admin', 'groups.read'),
+    ('admin', 'groups.manage');
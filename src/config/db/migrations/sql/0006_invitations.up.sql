@@ -0,0 +1,15 @@
+-- Invitaciones a crear una cuenta. El token que recibe el invitado está firmado y lleva el ID de
+-- la invitación, así que no se guarda.
+CREATE TABLE invitations (
+    id           text        PRIMARY KEY,
+    tenant_id    text        NOT NULL REFERENCES organizations (id),
+    email        text        NOT NULL,
+    role         text        NOT NULL REFERENCES roles (name),
+    invited_by   text        NOT NULL,
+    expires_at   timestamptz NOT NULL,
+    accepted_at  timestamptz,
+    revoked_at   timestamptz,
+    created_at   timestamptz
+);
+
+CREATE INDEX idx_invitations_tenant_email ON invitations (tenant_id, email);
@@ -0,0 +1,3 @@
+DELETE FROM permissions WHERE name = 'users.status.manage';
+DROP INDEX IF EXISTS idx_users_tenant_status;
+ALTER TABLE users DROP COLUMN status_changed_at, DROP COLUMN status_reason, DROP COLUMN status;
@@ -0,0 +1,15 @@
+-- Estado de las cuentas. Las existentes quedan activas; solo las activas inician sesión y usan
+-- sus tokens. status_reason es el motivo del último cambio de estado.
+ALTER TABLE users
+    ADD COLUMN status text NOT NULL DEFAULT 'active'
+        CHECK (status IN ('pending', 'active', 'suspended', 'disabled')),
+    ADD COLUMN status_reason text NOT NULL DEFAULT '',
+    ADD COLUMN status_changed_at timestamptz;
+
+CREATE INDEX idx_users_tenant_status ON users (tenant_id, status);
+
+INSERT INTO permissions (name, description) VALUES
+    ('users.status.manage', 'Activar, suspender o deshabilitar cuentas');
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('admin', 'users.status.manage');
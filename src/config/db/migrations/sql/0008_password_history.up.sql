@@ -0,0 +1,14 @@
+-- Cambios de contraseña. password_changed_at invalida los tokens emitidos antes del último cambio y
+-- password_history guarda los hashes anteriores para no permitir reutilizarlos.
+ALTER TABLE users
+    ADD COLUMN password_changed_at timestamptz;
+
+CREATE TABLE password_history (
+    id          text        PRIMARY KEY,
+    tenant_id   text        NOT NULL REFERENCES organizations (id),
+    user_id     text        NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    password    text        NOT NULL,
+    created_at  timestamptz NOT NULL
+);
+
+CREATE INDEX idx_password_history_user_created ON password_history (user_id, created_at);
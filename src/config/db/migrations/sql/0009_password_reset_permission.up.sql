@@ -0,0 +1,7 @@
+-- Cambiar la contraseña de otro usuario sin la actual deja de depender de users.write.any, que
+-- también tiene la API key de cada organización
+INSERT INTO permissions (name, description) VALUES
+    ('users.password.reset', 'Cambiar la contraseña de cualquier usuario sin conocer la actual');
+
+INSERT INTO role_permissions (role_name, permission_name) VALUES
+    ('admin', 'users.password.reset');
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"users-api/src/client"
+	"users-api/src/models"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConnectSQLite abre una base SQLite para tests y desarrollo local. Las migraciones SQL son
+// específicas de PostgreSQL, por eso el esquema se crea con AutoMigrate desde los modelos.
+func ConnectSQLite(path string, logger *zap.Logger) (*gorm.DB, error) {
+	sqliteDB, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	if err != nil {
+		logger.Error("[USERS-API] Error al abrir SQLite", zap.Error(err))
+		return nil, err
+	}
+
+	// Con :memory: cada conexión es una base distinta, se usa una sola conexión
+	sqlDB, err := sqliteDB.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := sqliteDB.AutoMigrate(&models.Organization{}, &models.User{}, &models.Permission{}, &models.Role{}, &models.Group{}, &models.GroupMember{}, &models.Invitation{}, &models.PasswordHistory{}); err != nil {
+		logger.Error("[USERS-API] Error al crear el esquema en SQLite", zap.Error(err))
+		return nil, err
+	}
+
+	// Los roles y la organización iniciales que en PostgreSQL insertan las migraciones 0003 y 0004
+	if err := sqliteDB.Clauses(clause.OnConflict{DoNothing: true}).Create(client.DefaultRoles()).Error; err != nil {
+		logger.Error("[USERS-API] Error al crear los roles en SQLite", zap.Error(err))
+		return nil, err
+	}
+	if err := sqliteDB.Clauses(clause.OnConflict{DoNothing: true}).Create(client.DefaultOrganization()).Error; err != nil {
+		logger.Error("[USERS-API] Error al crear la organización por defecto en SQLite", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Info("[USERS-API] Conexión a SQLite establecida", zap.String("path", path))
+	return sqliteDB, nil
+}
@@ -1,34 +1,69 @@
 package log
 
 import (
-	"sync"
+	"fmt"
+	"os"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var (
-	logger     *zap.Logger
-	onceLogger sync.Once
+const (
+	ModeDevelopment = "development"
+	ModeProduction  = "production"
 )
 
-func GetLogger() *zap.Logger {
-	onceLogger.Do(func() {
-		var err error
-
-		config := zap.NewDevelopmentConfig()
+type Options struct {
+	Mode               string
+	Level              string
+	Sampling           bool
+	SamplingInitial    int
+	SamplingThereafter int
+	// Output reemplaza la salida estándar, se usa en los tests
+	Output zapcore.WriteSyncer
+}
 
-		config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
-		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-		config.EncoderConfig.TimeKey = "timestamp"
-		config.EncoderConfig.MessageKey = "message"
-		config.EncoderConfig.CallerKey = ""
-		config.EncoderConfig.NameKey = ""
+// NewLogger arma el logger en modo desarrollo (consola con colores) o producción (JSON).
+// En ambos modos los campos pasan por la capa de redacción antes de escribirse.
+func NewLogger(opts Options) (*zap.Logger, error) {
+	level, err := zapcore.ParseLevel(opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("nivel de log inválido %q", opts.Level)
+	}
 
-		logger, err = config.Build()
-		if err != nil {
-			panic(err)
+	var encoder zapcore.Encoder
+	output := opts.Output
+	switch opts.Mode {
+	case ModeProduction:
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.TimeKey = "timestamp"
+		encoderConfig.MessageKey = "message"
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+		if output == nil {
+			output = zapcore.Lock(os.Stdout)
+		}
+	case ModeDevelopment, "":
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		encoderConfig.TimeKey = "timestamp"
+		encoderConfig.MessageKey = "message"
+		encoderConfig.CallerKey = ""
+		encoderConfig.NameKey = ""
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+		if output == nil {
+			output = zapcore.Lock(os.Stderr)
 		}
-	})
-	return logger
+	default:
+		return nil, fmt.Errorf("modo de log inválido %q", opts.Mode)
+	}
+
+	core := NewRedactCore(zapcore.NewCore(encoder, output, level))
+	if opts.Sampling {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.SamplingInitial, opts.SamplingThereafter)
+	}
+
+	return zap.New(core, zap.ErrorOutput(zapcore.Lock(os.Stderr))), nil
 }
@@ -0,0 +1,127 @@
+package log
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys son las claves de campos cuyo valor nunca se escribe
+var sensitiveKeys = []string{"password", "passwd", "token", "authorization", "api_key", "apikey", "secret", "cookie", "hash"}
+
+var (
+	credentialsInURI = regexp.MustCompile(`(\w+://[^:/@\s]+:)[^@\s]+@`)
+	bearerToken      = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+	jwtToken         = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	passwordHash     = regexp.MustCompile(`\$(2[abxy]?|argon2id|argon2i)\$[^\s"']+`)
+	keyValueSecret   = regexp.MustCompile(`(?i)\b(password|passwd|pwd|token|secret|api_key|apikey)=[^\s&"']+`)
+	emailAddress     = regexp.MustCompile(`([A-Za-z0-9._%+\-])[A-Za-z0-9._%+\-]*@([A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+)
+
+// RedactString enmascara emails, tokens, hashes y credenciales dentro de un texto libre
+func RedactString(s string) string {
+	s = credentialsInURI.ReplaceAllString(s, "${1}"+redacted+"@")
+	s = bearerToken.ReplaceAllString(s, "$1 "+redacted)
+	s = jwtToken.ReplaceAllString(s, redacted)
+	s = passwordHash.ReplaceAllString(s, redacted)
+	s = keyValueSecret.ReplaceAllString(s, "$1="+redacted)
+	s = emailAddress.ReplaceAllString(s, "$1***@$2")
+	return s
+}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// revealed es el valor de un campo armado con Reveal
+type revealed string
+
+func (v revealed) String() string { return string(v) }
+
+// Reveal arma un campo que la redacción escribe tal cual. Solo es para valores que alguien tiene que
+// leer del log a propósito, como los tokens de INVITATION_NOTIFIER=log
+func Reveal(key, value string) zap.Field {
+	return zap.Stringer(key, revealed(value))
+}
+
+// RedactField oculta el valor de los campos sensibles por nombre y enmascara el contenido del resto
+func RedactField(field zapcore.Field) zapcore.Field {
+	if value, ok := field.Interface.(revealed); ok {
+		return zap.String(field.Key, string(value))
+	}
+	if isSensitiveKey(field.Key) {
+		return zap.String(field.Key, redacted)
+	}
+	if strings.Contains(strings.ToLower(field.Key), "email") && field.Type == zapcore.StringType {
+		masked := RedactString(field.String)
+		if masked == field.String {
+			masked = redacted
+		}
+		return zap.String(field.Key, masked)
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = RedactString(field.String)
+	case zapcore.ByteStringType:
+		if value, ok := field.Interface.([]byte); ok {
+			return zap.String(field.Key, RedactString(string(value)))
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			return zap.String(field.Key, RedactString(err.Error()))
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok {
+			return zap.String(field.Key, RedactString(stringer.String()))
+		}
+	case zapcore.ArrayMarshalerType, zapcore.ObjectMarshalerType, zapcore.ReflectType:
+		// Se serializa a texto para poder enmascararlo, pierde la estructura pero no filtra datos
+		return zap.String(field.Key, RedactString(fmt.Sprintf("%+v", field.Interface)))
+	}
+	return field
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redactedFields := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redactedFields[i] = RedactField(field)
+	}
+	return redactedFields
+}
+
+type redactCore struct {
+	zapcore.Core
+}
+
+// NewRedactCore envuelve un core para que ningún campo ni mensaje escriba datos personales o secretos
+func NewRedactCore(core zapcore.Core) zapcore.Core {
+	return &redactCore{Core: core}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = RedactString(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
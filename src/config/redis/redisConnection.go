@@ -3,7 +3,6 @@ package redis
 import (
 	"log"
 	"sync"
-	"users-api/src/config/envs"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -11,11 +10,13 @@ import (
 var once sync.Once
 var redisClient *redis.Client
 
-func ConnectRedis() *redis.Client {
-	REDIS_URI := envs.LoadEnvs(".env").Get("REDIS_URI")
+func ConnectRedis(uri string) *redis.Client {
+	if uri == "" {
+		return nil
+	}
 
 	once.Do(func() {
-		opt, err := redis.ParseURL(REDIS_URI)
+		opt, err := redis.ParseURL(uri)
 		if err != nil {
 			log.Printf("Error al parsear la URI de Redis: %v", err)
 			return
@@ -2,8 +2,8 @@ package controllers
 
 import (
 	"net/http"
+	"users-api/src/config/log"
 	"users-api/src/dto"
-	"users-api/src/errors"
 	"users-api/src/services"
 
 	"github.com/gin-gonic/gin"
@@ -11,21 +11,23 @@ import (
 )
 
 type AuthController struct {
-	service services.AuthService
-	logger  *zap.Logger
+	service      services.AuthService
+	tokenService services.TokenService
+	logger       *zap.Logger
 }
 
-func NewAuthController(service services.AuthService, logger *zap.Logger) *AuthController {
+func NewAuthController(service services.AuthService, tokenService services.TokenService, logger *zap.Logger) *AuthController {
 	return &AuthController{
-		service: service,
-		logger:  logger,
+		service:      service,
+		tokenService: tokenService,
+		logger:       logger,
 	}
 }
 
 func (ac *AuthController) Login(c *gin.Context) {
 	loginDTO := &dto.LoginDTO{}
 	if err := c.BindJSON(loginDTO); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
+		errorJSON(c, http.StatusBadRequest, gin.H{
 			"error": "Error al procesar la solicitud",
 			"code":  "INVALID_REQUEST",
 		})
@@ -34,19 +36,21 @@ func (ac *AuthController) Login(c *gin.Context) {
 
 	user, err := ac.service.Login(c.Request.Context(), loginDTO)
 	if err != nil {
-		if customErr, ok := err.(*errors.Error); ok {
-			c.JSON(customErr.HTTPStatusCode, gin.H{
-				"error": customErr.Message,
-				"code":  customErr.Code,
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "Internal server error",
-				"code":  "INTERNAL_SERVER_ERROR",
-			})
-		}
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	response := &dto.LoginResponseDTO{UserResponseDTO: *user}
+	if ac.tokenService.Enabled() {
+		token, expiresAt, err := ac.tokenService.Issue(c.Request.Context(), user)
+		if err != nil {
+			log.FromContext(c.Request.Context(), ac.logger).Error("[USERS-API]: Error al emitir token de acceso", zap.Error(err))
+			respondError(c, err)
+			return
+		}
+		response.AccessToken = token
+		response.ExpiresAt = &expiresAt
+	}
+
+	c.JSON(http.StatusOK, response)
 }
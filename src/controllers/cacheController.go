@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"users-api/src/config/log"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type CacheController struct {
+	service services.CacheService
+	logger  *zap.Logger
+}
+
+func NewCacheController(service services.CacheService, logger *zap.Logger) *CacheController {
+	return &CacheController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// WarmUp maneja la solicitud POST /admin/cache/warmup para precargar los usuarios más recientes
+func (cc *CacheController) WarmUp(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), cc.logger)
+	limit := services.DefaultWarmUpLimit
+	if rawLimit := c.Query("limit"); rawLimit != "" {
+		parsed, err := strconv.Atoi(rawLimit)
+		if err != nil || parsed <= 0 {
+			errorJSON(c, http.StatusBadRequest, gin.H{"error": "El límite debe ser un número positivo"})
+			return
+		}
+		limit = parsed
+	}
+
+	warmed, err := cc.service.WarmUp(c.Request.Context(), limit)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al precargar caché", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"warmed": warmed})
+}
+
+// GetStats maneja la solicitud GET /admin/cache/stats
+func (cc *CacheController) GetStats(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), cc.logger)
+	stats, err := cc.service.Stats(c.Request.Context())
+	if err != nil {
+		logger.Error("[USERS-API]: Error al obtener estadísticas de caché", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// EvictUser maneja la solicitud DELETE /admin/cache/users/:id
+func (cc *CacheController) EvictUser(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), cc.logger)
+	id := c.Param("id")
+
+	if err := cc.service.EvictUser(c.Request.Context(), id); err != nil {
+		logger.Error("[USERS-API]: Error al eliminar usuario de caché", zap.String("id", id), zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Flush maneja la solicitud DELETE /admin/cache para vaciar la caché de usuarios
+func (cc *CacheController) Flush(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), cc.logger)
+	deleted, err := cc.service.Flush(c.Request.Context())
+	if err != nil {
+		logger.Error("[USERS-API]: Error al vaciar caché", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
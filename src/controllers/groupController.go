@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type GroupController struct {
+	service services.GroupService
+	logger  *zap.Logger
+}
+
+func NewGroupController(service services.GroupService, logger *zap.Logger) *GroupController {
+	return &GroupController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// bindPage lee ?limit=&offset= y responde 400 si son inválidos
+func bindPage(c *gin.Context) (dto.PageQuery, bool) {
+	var page dto.PageQuery
+	if err := c.ShouldBindQuery(&page); err != nil {
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "limit debe estar entre 1 y 200 y offset no puede ser negativo"})
+		return page, false
+	}
+	return page, true
+}
+
+// bindMembers lee el cuerpo de POST y DELETE /groups/:id/members
+func bindMembers(c *gin.Context) (*dto.GroupMembersDTO, bool) {
+	var members dto.GroupMembersDTO
+	if err := c.ShouldBindJSON(&members); err != nil {
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "user_ids debe ser un array de entre 1 y 500 IDs"})
+		return nil, false
+	}
+	return &members, true
+}
+
+// ListGroups maneja la solicitud GET /groups
+func (gc *GroupController) ListGroups(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	groups, err := gc.service.ListGroups(c.Request.Context(), page)
+	if err != nil {
+		log.FromContext(c.Request.Context(), gc.logger).Error("[USERS-API]: Error al obtener grupos", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, groups)
+}
+
+// GetGroup maneja la solicitud GET /groups/:id
+func (gc *GroupController) GetGroup(c *gin.Context) {
+	group, err := gc.service.GetGroup(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// CreateGroup maneja la solicitud POST /groups
+func (gc *GroupController) CreateGroup(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), gc.logger)
+
+	var createGroupDTO dto.CreateGroupDTO
+	if err := c.ShouldBindJSON(&createGroupDTO); err != nil {
+		logger.Error("[USERS-API]: Error al procesar datos del grupo", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	group, err := gc.service.CreateGroup(c.Request.Context(), &createGroupDTO)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al crear grupo", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateGroup maneja la solicitud PUT /groups/:id
+func (gc *GroupController) UpdateGroup(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), gc.logger)
+	id := c.Param("id")
+
+	var updateGroupDTO dto.UpdateGroupDTO
+	if err := c.ShouldBindJSON(&updateGroupDTO); err != nil {
+		logger.Error("[USERS-API]: Error al procesar datos del grupo", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	group, err := gc.service.UpdateGroup(c.Request.Context(), id, &updateGroupDTO)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al actualizar grupo", zap.String("id", id), zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup maneja la solicitud DELETE /groups/:id
+func (gc *GroupController) DeleteGroup(c *gin.Context) {
+	id := c.Param("id")
+	if err := gc.service.DeleteGroup(c.Request.Context(), id); err != nil {
+		log.FromContext(c.Request.Context(), gc.logger).Error("[USERS-API]: Error al eliminar grupo", zap.String("id", id), zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListMembers maneja la solicitud GET /groups/:id/members
+func (gc *GroupController) ListMembers(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	members, err := gc.service.ListMembers(c.Request.Context(), c.Param("id"), page)
+	if err != nil {
+		log.FromContext(c.Request.Context(), gc.logger).Error("[USERS-API]: Error al obtener miembros", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMembers maneja la solicitud POST /groups/:id/members
+func (gc *GroupController) AddMembers(c *gin.Context) {
+	members, ok := bindMembers(c)
+	if !ok {
+		return
+	}
+
+	result, err := gc.service.AddMembers(c.Request.Context(), c.Param("id"), members.UserIDs)
+	if err != nil {
+		log.FromContext(c.Request.Context(), gc.logger).Error("[USERS-API]: Error al agregar miembros", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// RemoveMembers maneja la solicitud DELETE /groups/:id/members
+func (gc *GroupController) RemoveMembers(c *gin.Context) {
+	members, ok := bindMembers(c)
+	if !ok {
+		return
+	}
+
+	result, err := gc.service.RemoveMembers(c.Request.Context(), c.Param("id"), members.UserIDs)
+	if err != nil {
+		log.FromContext(c.Request.Context(), gc.logger).Error("[USERS-API]: Error al quitar miembros", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListUserGroups maneja la solicitud GET /users/:id/groups
+func (gc *GroupController) ListUserGroups(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	groups, err := gc.service.ListUserGroups(c.Request.Context(), c.Param("id"), page)
+	if err != nil {
+		log.FromContext(c.Request.Context(), gc.logger).Error("[USERS-API]: Error al obtener grupos del usuario", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, groups)
+}
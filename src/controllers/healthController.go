@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type HealthController struct {
+	service services.HealthService
+	logger  *zap.Logger
+}
+
+func NewHealthController(service services.HealthService, logger *zap.Logger) *HealthController {
+	return &HealthController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Liveness maneja la solicitud GET /healthz
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, hc.service.Liveness(c.Request.Context()))
+}
+
+// Readiness maneja la solicitud GET /readyz, responde 503 solo si falla una dependencia crítica
+func (hc *HealthController) Readiness(c *gin.Context) {
+	report := hc.service.Readiness(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == services.HealthStatusFail {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type InvitationController struct {
+	service services.InvitationService
+	logger  *zap.Logger
+}
+
+func NewInvitationController(service services.InvitationService, logger *zap.Logger) *InvitationController {
+	return &InvitationController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateInvitation maneja la solicitud POST /users/invitations
+func (ic *InvitationController) CreateInvitation(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), ic.logger)
+
+	var createInvitationDTO dto.CreateInvitationDTO
+	if err := c.ShouldBindJSON(&createInvitationDTO); err != nil {
+		logger.Error("[USERS-API]: Error al procesar datos de la invitación", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	invitation, err := ic.service.CreateInvitation(c.Request.Context(), &createInvitationDTO)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al crear invitación", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// ListInvitations maneja la solicitud GET /users/invitations
+func (ic *InvitationController) ListInvitations(c *gin.Context) {
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	invitations, err := ic.service.ListInvitations(c.Request.Context(), page)
+	if err != nil {
+		log.FromContext(c.Request.Context(), ic.logger).Error("[USERS-API]: Error al obtener invitaciones", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeInvitation maneja la solicitud DELETE /users/invitations/:id
+func (ic *InvitationController) RevokeInvitation(c *gin.Context) {
+	id := c.Param("id")
+	if err := ic.service.RevokeInvitation(c.Request.Context(), id); err != nil {
+		log.FromContext(c.Request.Context(), ic.logger).Error("[USERS-API]: Error al revocar invitación", zap.String("id", id), zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// AcceptInvitation maneja la solicitud POST /users/invitations/accept
+func (ic *InvitationController) AcceptInvitation(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), ic.logger)
+
+	var acceptInvitationDTO dto.AcceptInvitationDTO
+	if err := c.ShouldBindJSON(&acceptInvitationDTO); err != nil {
+		logger.Error("[USERS-API]: Error al procesar datos para aceptar la invitación", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	user, err := ic.service.AcceptInvitation(c.Request.Context(), &acceptInvitationDTO)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al aceptar invitación", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, user)
+}
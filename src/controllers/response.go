@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/src/config/log"
+	"users-api/src/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorJSON responde con un cuerpo de error que incluye el ID de la solicitud para correlacionar con los logs
+func errorJSON(c *gin.Context, status int, body gin.H) {
+	if requestID := log.RequestIDFromContext(c.Request.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+	c.JSON(status, body)
+}
+
+// respondError responde con el código y mensaje del error de aplicación, o con un 500 si no lo es
+func respondError(c *gin.Context, err error) {
+	if customErr, ok := err.(*errors.Error); ok {
+		errorJSON(c, customErr.HTTPStatusCode, gin.H{
+			"error": customErr.Message,
+			"code":  customErr.Code,
+		})
+		return
+	}
+
+	errorJSON(c, http.StatusInternalServerError, gin.H{
+		"error": "Internal server error",
+		"code":  "INTERNAL_SERVER_ERROR",
+	})
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+	"users-api/src/config/log"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type RoleController struct {
+	service services.RoleService
+	logger  *zap.Logger
+}
+
+func NewRoleController(service services.RoleService, logger *zap.Logger) *RoleController {
+	return &RoleController{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListRoles maneja la solicitud GET /roles para listar los roles con sus permisos
+func (rc *RoleController) ListRoles(c *gin.Context) {
+	roles, err := rc.service.ListRoles(c.Request.Context())
+	if err != nil {
+		log.FromContext(c.Request.Context(), rc.logger).Error("[USERS-API]: Error al obtener roles", zap.Error(err))
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
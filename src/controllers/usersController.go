@@ -1,8 +1,12 @@
 package controllers
 
 import (
+	"io"
 	"net/http"
+	"users-api/src/config/log"
 	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/models"
 	"users-api/src/services"
 
 	"github.com/gin-gonic/gin"
@@ -21,102 +25,137 @@ func NewUserController(service services.UserService, logger *zap.Logger) *UserCo
 	}
 }
 
-// GetUsers maneja la solicitud GET /users/ para obtener todos los usuarios o aplicar un filtro
+// GetUsers maneja la solicitud GET /users/ para obtener todos los usuarios o aplicar un filtro.
+// ?status= filtra por estado de la cuenta
 func (uc *UserController) GetUsers(c *gin.Context) {
-	uc.logger.Info("[USERS-API]: Iniciando obtención de usuarios")
+	logger := log.FromContext(c.Request.Context(), uc.logger)
+	logger.Info("[USERS-API]: Iniciando obtención de usuarios")
 
 	filter := make(map[string]interface{})
-	if err := c.BindJSON(&filter); err != nil && err.Error() != "EOF" {
-		uc.logger.Error("[USERS-API]: Error al procesar filtro", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Error al procesar el filtro"})
+	// El filtro es opcional, un cuerpo vacío no es un error
+	if err := c.ShouldBindJSON(&filter); err != nil && err != io.EOF {
+		logger.Error("[USERS-API]: Error al procesar filtro", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Error al procesar el filtro"})
 		return
 	}
+	if status, ok := c.GetQuery("status"); ok {
+		if !models.IsUserStatus(status) {
+			respondError(c, errors.ErrInvalidStatus)
+			return
+		}
+		filter["status"] = status
+	}
 
 	users, err := uc.service.GetAllUsers(c.Request.Context(), filter)
 	if err != nil {
-		uc.logger.Error("[USERS-API]: Error al obtener usuarios", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener usuarios"})
+		logger.Error("[USERS-API]: Error al obtener usuarios", zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusInternalServerError, gin.H{"error": "Error al obtener usuarios"})
 		return
 	}
 
-	uc.logger.Info("[USERS-API]: Usuarios obtenidos exitosamente", zap.Int("count", len(users)))
+	logger.Info("[USERS-API]: Usuarios obtenidos exitosamente", zap.Int("count", len(users)))
 	c.JSON(http.StatusOK, users)
 }
 
 func (uc *UserController) GetUsersList(c *gin.Context) {
-	uc.logger.Info("[USERS-API]: Iniciando obtención de lista de usuarios por IDs")
+	logger := log.FromContext(c.Request.Context(), uc.logger)
+	logger.Info("[USERS-API]: Iniciando obtención de lista de usuarios por IDs")
 
 	var requestBody struct {
 		IDs []string `json:"ids" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
-		uc.logger.Error("[USERS-API]: Error al procesar los IDs", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Los IDs son requeridos y deben ser un array de strings"})
+		logger.Error("[USERS-API]: Error al procesar los IDs", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Los IDs son requeridos y deben ser un array de strings"})
 		return
 	}
 
 	if len(requestBody.IDs) == 0 {
-		uc.logger.Warn("[USERS-API]: Se recibió una lista vacía de IDs")
-		c.JSON(http.StatusBadRequest, gin.H{"error": "La lista de IDs no puede estar vacía"})
+		logger.Warn("[USERS-API]: Se recibió una lista vacía de IDs")
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "La lista de IDs no puede estar vacía"})
 		return
 	}
 
 	users, err := uc.service.GetUsersList(c.Request.Context(), requestBody.IDs)
 	if err != nil {
-		uc.logger.Error("[USERS-API]: Error al obtener lista de usuarios", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al obtener usuarios"})
+		logger.Error("[USERS-API]: Error al obtener lista de usuarios", zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusInternalServerError, gin.H{"error": "Error al obtener usuarios"})
 		return
 	}
 
-	uc.logger.Info("[USERS-API]: Lista de usuarios obtenida exitosamente", zap.Int("count", len(users)))
+	logger.Info("[USERS-API]: Lista de usuarios obtenida exitosamente", zap.Int("count", len(users)))
 	c.JSON(http.StatusOK, users)
 }
 
 func (uc *UserController) GetUserByEmail(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
 	email := c.Param("email")
-	uc.logger.Info("[USERS-API]: Buscando usuario por email", zap.String("email", email))
+	logger.Info("[USERS-API]: Buscando usuario por email", zap.String("email", email))
 
 	user, err := uc.service.GetUserByEmail(c.Request.Context(), email)
 	if err != nil {
-		uc.logger.Error("[USERS-API]: Usuario no encontrado por email", zap.String("email", email), zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+		logger.Error("[USERS-API]: Usuario no encontrado por email", zap.String("email", email), zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
 		return
 	}
 
-	uc.logger.Info("[USERS-API]: Usuario encontrado exitosamente por email", zap.String("email", email))
+	logger.Info("[USERS-API]: Usuario encontrado exitosamente por email", zap.String("email", email))
 	c.JSON(http.StatusOK, user)
 }
 
 // GetUserByID maneja la solicitud GET /users/:id para obtener un usuario por su ID
 func (uc *UserController) GetUserByID(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
 	id := c.Param("id")
-	uc.logger.Info("[USERS-API]: Buscando usuario por ID", zap.String("id", id))
+	logger.Info("[USERS-API]: Buscando usuario por ID", zap.String("id", id))
 
 	user, err := uc.service.GetUserByID(c.Request.Context(), id)
 	if err != nil {
-		uc.logger.Error("[USERS-API]: Usuario no encontrado por ID", zap.String("id", id), zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
+		logger.Error("[USERS-API]: Usuario no encontrado por ID", zap.String("id", id), zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusNotFound, gin.H{"error": "Usuario no encontrado"})
 		return
 	}
 
-	uc.logger.Info("[USERS-API]: Usuario encontrado exitosamente por ID", zap.String("id", id))
+	logger.Info("[USERS-API]: Usuario encontrado exitosamente por ID", zap.String("id", id))
 	c.JSON(http.StatusOK, user)
 }
 
 // CreateUser maneja la solicitud POST /users/ para crear un nuevo usuario
 func (uc *UserController) CreateUser(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
 	var createUserDTO dto.CreateUserDTO
 
 	if err := c.ShouldBindJSON(&createUserDTO); err != nil {
-		uc.logger.Error("Error al procesar datos de usuario", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		logger.Error("Error al procesar datos de usuario", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
 		return
 	}
 
 	userResponse, err := uc.service.CreateUser(c.Request.Context(), &createUserDTO)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al crear el usuario"})
+		logger.Error("[USERS-API]: Error al crear usuario", zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusInternalServerError, gin.H{"error": "Error al crear el usuario"})
 		return
 	}
 
@@ -125,38 +164,90 @@ func (uc *UserController) CreateUser(c *gin.Context) {
 
 // UpdateUser maneja la solicitud PUT /users/:id para actualizar un usuario existente
 func (uc *UserController) UpdateUser(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
 	id := c.Param("id")
-	uc.logger.Info("[USERS-API]: Iniciando actualización de usuario", zap.String("id", id))
+	logger.Info("[USERS-API]: Iniciando actualización de usuario", zap.String("id", id))
 
 	var updateUserDTO dto.UpdateUserDTO
 	if err := c.ShouldBindJSON(&updateUserDTO); err != nil {
-		uc.logger.Error("[USERS-API]: Error al procesar datos de actualización", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		logger.Error("[USERS-API]: Error al procesar datos de actualización", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
 		return
 	}
 
 	userResponse, err := uc.service.UpdateUser(c.Request.Context(), id, &updateUserDTO)
 	if err != nil {
-		uc.logger.Error("[USERS-API]: Error al actualizar usuario", zap.String("id", id), zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al actualizar el usuario"})
+		logger.Error("[USERS-API]: Error al actualizar usuario", zap.String("id", id), zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusInternalServerError, gin.H{"error": "Error al actualizar el usuario"})
 		return
 	}
 
-	uc.logger.Info("[USERS-API]: Usuario actualizado exitosamente", zap.String("id", id))
+	logger.Info("[USERS-API]: Usuario actualizado exitosamente", zap.String("id", id))
+	c.JSON(http.StatusOK, userResponse)
+}
+
+// UpdateUserStatus maneja la solicitud PUT /users/:id/status para activar, suspender o deshabilitar una cuenta
+func (uc *UserController) UpdateUserStatus(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
+	id := c.Param("id")
+
+	var updateStatusDTO dto.UpdateUserStatusDTO
+	if err := c.ShouldBindJSON(&updateStatusDTO); err != nil {
+		logger.Error("[USERS-API]: Error al procesar el cambio de estado", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "status debe ser pending, active, suspended o disabled y reason es obligatorio"})
+		return
+	}
+
+	userResponse, err := uc.service.ChangeStatus(c.Request.Context(), id, updateStatusDTO.Status, updateStatusDTO.Reason)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al cambiar el estado del usuario", zap.String("id", id), zap.Error(err))
+		respondError(c, err)
+		return
+	}
 	c.JSON(http.StatusOK, userResponse)
 }
 
+// ChangePassword maneja la solicitud PUT /users/:id/password. Los tokens del usuario, también el de
+// la solicitud, dejan de servir y hay que volver a iniciar sesión
+func (uc *UserController) ChangePassword(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
+	id := c.Param("id")
+
+	var changePasswordDTO dto.ChangePasswordDTO
+	if err := c.ShouldBindJSON(&changePasswordDTO); err != nil {
+		logger.Error("[USERS-API]: Error al procesar el cambio de contraseña", zap.Error(err))
+		errorJSON(c, http.StatusBadRequest, gin.H{"error": "new_password es obligatoria"})
+		return
+	}
+
+	if err := uc.service.ChangePassword(c.Request.Context(), id, &changePasswordDTO); err != nil {
+		logger.Error("[USERS-API]: Error al cambiar la contraseña", zap.String("id", id), zap.Error(err))
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
 // DeleteUser maneja la solicitud DELETE /users/:id para eliminar un usuario existente
 func (uc *UserController) DeleteUser(c *gin.Context) {
+	logger := log.FromContext(c.Request.Context(), uc.logger)
 	id := c.Param("id")
-	uc.logger.Info("[USERS-API]: Iniciando eliminación de usuario", zap.String("id", id))
+	logger.Info("[USERS-API]: Iniciando eliminación de usuario", zap.String("id", id))
 
 	if err := uc.service.DeleteUser(c.Request.Context(), id); err != nil {
-		uc.logger.Error("[USERS-API]: Error al eliminar usuario", zap.String("id", id), zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error al eliminar el usuario"})
+		logger.Error("[USERS-API]: Error al eliminar usuario", zap.String("id", id), zap.Error(err))
+		if _, ok := err.(*errors.Error); ok {
+			respondError(c, err)
+			return
+		}
+		errorJSON(c, http.StatusInternalServerError, gin.H{"error": "Error al eliminar el usuario"})
 		return
 	}
 
-	uc.logger.Info("[USERS-API]: Usuario eliminado exitosamente", zap.String("id", id))
+	logger.Info("[USERS-API]: Usuario eliminado exitosamente", zap.String("id", id))
 	c.Status(http.StatusNoContent)
 }
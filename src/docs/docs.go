@@ -0,0 +1,35 @@
+// Package docs sirve la especificación OpenAPI 3 de la API y una página para navegarla,
+// ambas embebidas en el binario para no depender de recursos externos.
+package docs
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed index.html
+var page []byte
+
+// Spec devuelve el documento OpenAPI tal como se sirve en /openapi.json
+func Spec() []byte {
+	return spec
+}
+
+// SpecHandler sirve el documento OpenAPI
+func SpecHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(spec)
+	})
+}
+
+// PageHandler sirve la página de documentación, que lee /openapi.json desde el navegador
+func PageHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	})
+}
@@ -0,0 +1,8 @@
+package dto
+
+// ChangePasswordDTO es el cuerpo de PUT /users/:id/password. CurrentPassword es obligatoria salvo para
+// quien tiene users.password.reset
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 	"time"
+	"users-api/src/auth"
 	"users-api/src/utils"
 
 	"github.com/google/uuid"
@@ -16,11 +17,13 @@ type CreateUserDTO struct {
 	Birthdate time.Time `json:"birthdate" binding:"required"`
 	Role      string    `json:"role"`
 	Email     string    `json:"email" binding:"required,email"`
-	Password  string    `json:"password" binding:"required,min=6"`
+	Password  string    `json:"password" binding:"required"`
 	Avatar    string    `json:"avatar"`
+	// Status es active por defecto; pending crea una cuenta que no puede iniciar sesión hasta activarla
+	Status string `json:"status" binding:"omitempty,oneof=pending active"`
 }
 
-func (dto *CreateUserDTO) ValidateAndHash() (string, error) {
+func (dto *CreateUserDTO) ValidateAndHash(hasher utils.PasswordHasher) (string, error) {
 	if strings.TrimSpace(dto.Name) == "" {
 		return "", errors.New("el nombre es obligatorio")
 	}
@@ -34,16 +37,16 @@ func (dto *CreateUserDTO) ValidateAndHash() (string, error) {
 	}
 
 	if strings.TrimSpace(dto.Password) == "" {
-		return "", errors.New("la contraseña es obligatoria y debe tener al menos 6 caracteres")
+		return "", errors.New("la contraseña es obligatoria")
 	}
 
-	hashedPassword, err := utils.HashPassword(dto.Password)
+	hashedPassword, err := hasher.Hash(dto.Password)
 	if err != nil {
 		return "", err
 	}
 
 	if strings.TrimSpace(dto.Role) == "" {
-		dto.Role = "user"
+		dto.Role = auth.DefaultRole
 	}
 
 	if strings.TrimSpace(dto.Avatar) == "" {
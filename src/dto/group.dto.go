@@ -0,0 +1,40 @@
+package dto
+
+import "time"
+
+// GroupDTO es un grupo de usuarios, sin sus miembros (ver GET /groups/:id/members)
+type GroupDTO struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CreateGroupDTO struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+// UpdateGroupDTO reemplaza los campos informados; una descripción vacía la borra
+type UpdateGroupDTO struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+}
+
+// GroupMembersDTO es el cuerpo de POST y DELETE /groups/:id/members, con hasta 500 usuarios por solicitud
+type GroupMembersDTO struct {
+	UserIDs []string `json:"user_ids" binding:"required,min=1,max=500,dive,required"`
+}
+
+// GroupMembersResultDTO informa cuántos usuarios cambiaron de pertenencia; los que ya estaban
+// (o ya no estaban) no cuentan
+type GroupMembersResultDTO struct {
+	Added   int64 `json:"added"`
+	Removed int64 `json:"removed"`
+}
+
+type GroupPageDTO struct {
+	Groups []GroupDTO `json:"groups"`
+	PageDTO
+}
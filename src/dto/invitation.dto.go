@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// CreateInvitationDTO es el cuerpo de POST /users/invitations. Sin role se invita con auth.DefaultRole
+// y sin expires_at la invitación vence en INVITATION_TTL
+type CreateInvitationDTO struct {
+	Email     string     `json:"email" binding:"required,email"`
+	Role      string     `json:"role"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// InvitationDTO es una invitación pendiente. El token no se incluye, solo lo recibe el invitado por el notificador
+type InvitationDTO struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InvitationPageDTO struct {
+	Invitations []InvitationDTO `json:"invitations"`
+	PageDTO
+}
+
+// AcceptInvitationDTO es el cuerpo de POST /users/invitations/accept. El email y el rol salen de la invitación
+type AcceptInvitationDTO struct {
+	Token     string    `json:"token" binding:"required"`
+	Name      string    `json:"name" binding:"required"`
+	Lastname  string    `json:"lastname" binding:"required"`
+	Birthdate time.Time `json:"birthdate" binding:"required"`
+	Password  string    `json:"password" binding:"required"`
+	Avatar    string    `json:"avatar"`
+}
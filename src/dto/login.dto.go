@@ -1,6 +1,16 @@
 package dto
 
+import "time"
+
 type LoginDTO struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// LoginResponseDTO es el usuario autenticado y, si JWT_SECRET está configurado, un token de acceso
+// para enviar como "Authorization: Bearer <token>"
+type LoginResponseDTO struct {
+	UserResponseDTO
+	AccessToken string     `json:"access_token,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
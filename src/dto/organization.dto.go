@@ -0,0 +1,11 @@
+package dto
+
+import "time"
+
+// OrganizationDTO es una organización (tenant). La API key no se incluye, solo se muestra al crearla o rotarla
+type OrganizationDTO struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	HasAPIKey bool      `json:"has_api_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+const (
+	// DefaultPageLimit es la cantidad de elementos por página si no se indica limit
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageQuery son los parámetros de paginación de los listados (?limit=&offset=)
+type PageQuery struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+// Normalize aplica DefaultPageLimit si no se indicó limit
+func (q PageQuery) Normalize() PageQuery {
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageLimit
+	}
+	if q.Limit > MaxPageLimit {
+		q.Limit = MaxPageLimit
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	return q
+}
+
+// PageDTO describe la página devuelta; Total es la cantidad de elementos sin paginar
+type PageDTO struct {
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+type UserPageDTO struct {
+	Users []UserResponseDTO `json:"users"`
+	PageDTO
+}
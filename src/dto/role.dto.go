@@ -0,0 +1,8 @@
+package dto
+
+// RoleDTO es un rol con los permisos que otorga
+type RoleDTO struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -10,6 +10,8 @@ type UpdateUserDTO struct {
 	Birthdate *time.Time `json:"birthdate,omitempty"`
 	Role      *string    `json:"role,omitempty"`
 	Email     *string    `json:"email,omitempty"`
-	Password  *string    `json:"password,omitempty"`
-	Avatar    *string    `json:"avatar,omitempty"`
+	// Password se rechaza con errors.ErrPasswordInUpdate en lugar de ignorarla: la contraseña se
+	// cambia con PUT /users/:id/password
+	Password *string `json:"password,omitempty"`
+	Avatar   *string `json:"avatar,omitempty"`
 }
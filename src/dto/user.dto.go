@@ -11,4 +11,9 @@ type UserDTO struct {
 	Email     string    `json:"email"`
 	Password  string    `json:"password"`
 	Avatar    string    `json:"avatar"`
+	Status    string    `json:"status"`
+	// La caché de login guarda models.User, que no tiene tags JSON, y se lee en este DTO. encoding/json
+	// compara las claves sin distinguir mayúsculas, así que, como en los demás campos, el tag solo puede
+	// diferir del nombre del campo del modelo en mayúsculas: con password_changed_at no se leería
+	PasswordChangedAt *time.Time `json:"passwordchangedat"`
 }
@@ -11,6 +11,13 @@ type UserResponseDTO struct {
 	Role      string    `json:"role"`
 	Email     string    `json:"email"`
 	Avatar    string    `json:"avatar"`
+	TenantID  string    `json:"tenant_id"`
+	Status    string    `json:"status"`
+	// StatusReason y StatusChangedAt describen el último cambio de estado, si hubo alguno
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusChangedAt *time.Time `json:"status_changed_at,omitempty"`
+	// PasswordChangedAt es el último cambio de contraseña; los tokens emitidos antes ya no sirven
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
 }
 
 type UsersResponseDto []UserResponseDTO
@@ -0,0 +1,7 @@
+package dto
+
+// UpdateUserStatusDTO es el cuerpo de PUT /users/:id/status
+type UpdateUserStatusDTO struct {
+	Status string `json:"status" binding:"required,oneof=pending active suspended disabled"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
@@ -9,6 +9,8 @@ type Error struct {
 	Code           string
 	Message        string
 	HTTPStatusCode int
+	// base es el error declarado del que se derivó con WithMessage
+	base *Error
 }
 
 func (e *Error) Error() string {
@@ -23,13 +25,67 @@ func NewError(code, message string, httpStatusCode int) *Error {
 	}
 }
 
+// WithMessage devuelve una copia de e con otro mensaje. errors.Is la sigue reconociendo como e
+func (e *Error) WithMessage(message string) *Error {
+	return &Error{
+		Code:           e.Code,
+		Message:        message,
+		HTTPStatusCode: e.HTTPStatusCode,
+		base:           e,
+	}
+}
+
+func (e *Error) Unwrap() error {
+	if e.base == nil {
+		return nil
+	}
+	return e.base
+}
+
 var (
-	ErrInvalidData     = NewError("INVALID_DATA", "Datos inválidos", http.StatusBadRequest)
-	ErrUserNotFound    = NewError("USER_NOT_FOUND", "Usuario no encontrado", http.StatusNotFound)
-	ErrCourseNotFound  = NewError("COURSE_NOT_FOUND", "Curso no encontrado", http.StatusNotFound)
-	ErrInternalServer  = NewError("INTERNAL_SERVER_ERROR", "Error interno del servidor", http.StatusInternalServerError)
-	ErrDuplicateEnroll = NewError("DUPLICATE_ENROLL", "El estudiante ya está inscrito en este curso", http.StatusConflict)
-	ErrMissingUserId   = NewError("MISSING_USER_ID", "El ID de usuario es requerido", http.StatusBadRequest)
-	ErrMissingCourseId = NewError("MISSING_COURSE_ID", "El ID del curso es requerido", http.StatusBadRequest)
-	ErrNoResults       = NewError("NO_RESULTS", "No se encontraron resultados", http.StatusNotFound)
+	ErrInvalidData         = NewError("INVALID_DATA", "Datos inválidos", http.StatusBadRequest)
+	ErrUserNotFound        = NewError("USER_NOT_FOUND", "Usuario no encontrado", http.StatusNotFound)
+	ErrCourseNotFound      = NewError("COURSE_NOT_FOUND", "Curso no encontrado", http.StatusNotFound)
+	ErrInternalServer      = NewError("INTERNAL_SERVER_ERROR", "Error interno del servidor", http.StatusInternalServerError)
+	ErrDuplicateEnroll     = NewError("DUPLICATE_ENROLL", "El estudiante ya está inscrito en este curso", http.StatusConflict)
+	ErrMissingUserId       = NewError("MISSING_USER_ID", "El ID de usuario es requerido", http.StatusBadRequest)
+	ErrMissingCourseId     = NewError("MISSING_COURSE_ID", "El ID del curso es requerido", http.StatusBadRequest)
+	ErrNoResults           = NewError("NO_RESULTS", "No se encontraron resultados", http.StatusNotFound)
+	ErrCacheUnavailable    = NewError("CACHE_UNAVAILABLE", "La caché no está disponible", http.StatusServiceUnavailable)
+	ErrInvalidToken        = NewError("INVALID_TOKEN", "Token inválido o vencido", http.StatusUnauthorized)
+	ErrForbidden           = NewError("FORBIDDEN", "No tiene permisos para esta operación", http.StatusForbidden)
+	ErrInvalidAPIKey       = NewError("INVALID_API_KEY", "Invalid API Key", http.StatusUnauthorized)
+	ErrInvalidRole         = NewError("INVALID_ROLE", "El rol no existe", http.StatusBadRequest)
+	ErrInvalidOrg          = NewError("INVALID_ORGANIZATION", "El ID de organización debe tener entre 2 y 63 letras minúsculas, números o guiones", http.StatusBadRequest)
+	ErrOrgNotFound         = NewError("ORGANIZATION_NOT_FOUND", "Organización no encontrada", http.StatusNotFound)
+	ErrOrgExists           = NewError("ORGANIZATION_EXISTS", "La organización ya existe", http.StatusConflict)
+	ErrGroupNotFound       = NewError("GROUP_NOT_FOUND", "Grupo no encontrado", http.StatusNotFound)
+	ErrGroupExists         = NewError("GROUP_EXISTS", "Ya existe un grupo con ese nombre", http.StatusConflict)
+	ErrUnknownMembers      = NewError("UNKNOWN_USERS", "Algunos usuarios no existen en la organización", http.StatusBadRequest)
+	ErrUserExists          = NewError("USER_EXISTS", "Ya existe un usuario con ese email", http.StatusConflict)
+	ErrInvitationNotFound  = NewError("INVITATION_NOT_FOUND", "Invitación no encontrada o ya cerrada", http.StatusNotFound)
+	ErrInvitationExists    = NewError("INVITATION_EXISTS", "Ya hay una invitación pendiente para ese email", http.StatusConflict)
+	ErrInvalidInvitation   = NewError("INVALID_INVITATION", "La invitación es inválida, venció o ya fue usada", http.StatusBadRequest)
+	ErrInvalidExpiry       = NewError("INVALID_EXPIRY", "El vencimiento debe ser futuro y no superar los 30 días", http.StatusBadRequest)
+	ErrInvitationsDisabled = NewError("INVITATIONS_DISABLED", "Las invitaciones requieren JWT_SECRET", http.StatusServiceUnavailable)
+	ErrInvalidStatus       = NewError("INVALID_STATUS", "Estado de cuenta inválido", http.StatusBadRequest)
+	ErrStatusTransition    = NewError("INVALID_STATUS_TRANSITION", "La cuenta no puede pasar de su estado actual al pedido", http.StatusConflict)
+	ErrAccountPending      = NewError("ACCOUNT_PENDING", "La cuenta todavía no fue activada", http.StatusForbidden)
+	ErrAccountSuspended    = NewError("ACCOUNT_SUSPENDED", "La cuenta está suspendida", http.StatusForbidden)
+	ErrAccountDisabled     = NewError("ACCOUNT_DISABLED", "La cuenta está deshabilitada", http.StatusForbidden)
+	ErrNotificationFailed  = NewError("NOTIFICATION_FAILED", "No se pudo enviar la invitación", http.StatusBadGateway)
+	ErrPasswordBcryptLimit = NewError("PASSWORD_EXCEEDS_BCRYPT_LIMIT", "La contraseña supera los 72 bytes que admite bcrypt", http.StatusBadRequest)
+	ErrPasswordTooShort    = NewError("PASSWORD_TOO_SHORT", "La contraseña es demasiado corta", http.StatusBadRequest)
+	ErrPasswordTooLong     = NewError("PASSWORD_TOO_LONG", "La contraseña es demasiado larga", http.StatusBadRequest)
+	ErrPasswordNoLower     = NewError("PASSWORD_MISSING_LOWERCASE", "La contraseña debe tener al menos una minúscula", http.StatusBadRequest)
+	ErrPasswordNoUpper     = NewError("PASSWORD_MISSING_UPPERCASE", "La contraseña debe tener al menos una mayúscula", http.StatusBadRequest)
+	ErrPasswordNoDigit     = NewError("PASSWORD_MISSING_DIGIT", "La contraseña debe tener al menos un número", http.StatusBadRequest)
+	ErrPasswordNoSymbol    = NewError("PASSWORD_MISSING_SYMBOL", "La contraseña debe tener al menos un símbolo", http.StatusBadRequest)
+	ErrPasswordUserData    = NewError("PASSWORD_MATCHES_USER_DATA", "La contraseña no puede ser el email ni el nombre del usuario", http.StatusBadRequest)
+	ErrPasswordBreached    = NewError("PASSWORD_BREACHED", "La contraseña aparece en filtraciones conocidas, elegí otra", http.StatusBadRequest)
+	ErrPasswordReused      = NewError("PASSWORD_REUSED", "La contraseña es una de las últimas usadas", http.StatusBadRequest)
+	ErrPasswordInUpdate    = NewError("PASSWORD_CHANGE_NOT_ALLOWED", "La contraseña se cambia con PUT /users/:id/password", http.StatusBadRequest)
+	ErrCurrentPassword     = NewError("CURRENT_PASSWORD_REQUIRED", "Hay que indicar la contraseña actual", http.StatusBadRequest)
+	ErrWrongPassword       = NewError("INVALID_CURRENT_PASSWORD", "La contraseña actual es incorrecta", http.StatusForbidden)
+	ErrPasswordChanged     = NewError("PASSWORD_CHANGED", "La contraseña cambió mientras se procesaba la solicitud", http.StatusConflict)
 )
@@ -0,0 +1,255 @@
+package graph_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/config"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gqlResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+type gqlUser struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+func query(t *testing.T, server *apptest.Server, q string, variables map[string]interface{}) (int, gqlResponse) {
+	t.Helper()
+	resp := server.Do(t, http.MethodPost, "/graphql", map[string]interface{}{"query": q, "variables": variables})
+
+	var body gqlResponse
+	resp.Decode(t, &body)
+	return resp.StatusCode, body
+}
+
+func field(t *testing.T, body gqlResponse, name string, dest interface{}) {
+	t.Helper()
+	require.Empty(t, body.Errors)
+	require.NoError(t, json.Unmarshal(body.Data[name], dest))
+}
+
+const createUser = `mutation($input: CreateUserInput!) { createUser(input: $input) { id name email role } }`
+
+func newUser(t *testing.T, server *apptest.Server, name string) gqlUser {
+	t.Helper()
+	status, body := query(t, server, createUser, map[string]interface{}{"input": map[string]interface{}{
+		"name":      name,
+		"lastname":  "Test",
+		"birthdate": "1990-01-02T00:00:00Z",
+		"email":     name + "-" + uuid.NewString()[:8] + "@example.com",
+		"password":  "secreto123",
+	}})
+	require.Equal(t, http.StatusOK, status)
+
+	var user gqlUser
+	field(t, body, "createUser", &user)
+	return user
+}
+
+func TestQueries(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := newUser(t, server, "ana")
+	beto := newUser(t, server, "beto")
+
+	t.Run("user y userByEmail", func(t *testing.T) {
+		_, body := query(t, server, `query($id: ID!, $email: String!) { a: user(id: $id) { id name } b: userByEmail(email: $email) { id email } }`,
+			map[string]interface{}{"id": ana.ID, "email": beto.Email})
+
+		var a, b gqlUser
+		field(t, body, "a", &a)
+		field(t, body, "b", &b)
+		assert.Equal(t, ana.Name, a.Name)
+		assert.Equal(t, beto.ID, b.ID)
+	})
+
+	t.Run("inexistentes devuelven null sin error", func(t *testing.T) {
+		_, body := query(t, server, `query($id: ID!) { user(id: $id) { id } userByEmail(email: "nadie@example.com") { id } }`,
+			map[string]interface{}{"id": uuid.NewString()})
+		require.Empty(t, body.Errors)
+		assert.JSONEq(t, "null", string(body.Data["user"]))
+		assert.JSONEq(t, "null", string(body.Data["userByEmail"]))
+	})
+
+	t.Run("varios user(id) en una consulta", func(t *testing.T) {
+		var fields []string
+		for i, id := range []string{ana.ID, beto.ID, ana.ID, uuid.NewString()} {
+			fields = append(fields, fmt.Sprintf(`u%d: user(id: %q) { id }`, i, id))
+		}
+		_, body := query(t, server, "{ "+strings.Join(fields, " ")+" }", nil)
+		require.Empty(t, body.Errors)
+		assert.JSONEq(t, `{"id":"`+ana.ID+`"}`, string(body.Data["u0"]))
+		assert.JSONEq(t, `{"id":"`+beto.ID+`"}`, string(body.Data["u1"]))
+		assert.JSONEq(t, `{"id":"`+ana.ID+`"}`, string(body.Data["u2"]))
+		assert.JSONEq(t, "null", string(body.Data["u3"]))
+	})
+
+	t.Run("users pagina con cursor y filtra", func(t *testing.T) {
+		newUser(t, server, "carla")
+
+		type connection struct {
+			Edges []struct {
+				Cursor string  `json:"cursor"`
+				Node   gqlUser `json:"node"`
+			} `json:"edges"`
+			PageInfo struct {
+				HasNextPage bool    `json:"hasNextPage"`
+				EndCursor   *string `json:"endCursor"`
+			} `json:"pageInfo"`
+			TotalCount int `json:"totalCount"`
+		}
+		const users = `query($first: Int, $after: String, $filter: UserFilter) {
+			users(first: $first, after: $after, filter: $filter) { edges { cursor node { id name } } pageInfo { hasNextPage endCursor } totalCount }
+		}`
+
+		var seen []string
+		var after interface{}
+		for page := 0; page < 3; page++ {
+			_, body := query(t, server, users, map[string]interface{}{"first": 2, "after": after})
+			var conn connection
+			field(t, body, "users", &conn)
+			assert.Equal(t, 3, conn.TotalCount)
+			for _, edge := range conn.Edges {
+				seen = append(seen, edge.Node.ID)
+			}
+			if !conn.PageInfo.HasNextPage {
+				break
+			}
+			after = *conn.PageInfo.EndCursor
+		}
+		assert.Len(t, seen, 3)
+		assert.IsIncreasing(t, seen)
+
+		_, body := query(t, server, users, map[string]interface{}{"filter": map[string]interface{}{"name": "beto"}})
+		var conn connection
+		field(t, body, "users", &conn)
+		require.Len(t, conn.Edges, 1)
+		assert.Equal(t, beto.ID, conn.Edges[0].Node.ID)
+		assert.Equal(t, 1, conn.TotalCount)
+
+		_, body = query(t, server, users, map[string]interface{}{"after": "***"})
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "INVALID_CURSOR", body.Errors[0].Extensions["code"])
+	})
+
+	t.Run("GET acepta consultas", func(t *testing.T) {
+		resp := server.Do(t, http.MethodGet, "/graphql?query="+`%7B%20user(id%3A%22`+ana.ID+`%22)%20%7B%20name%20%7D%20%7D`, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(resp.Body))
+		assert.Contains(t, string(resp.Body), ana.Name)
+	})
+}
+
+func TestMutations(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := newUser(t, server, "ana")
+	assert.Equal(t, "user", ana.Role)
+
+	t.Run("updateUser", func(t *testing.T) {
+		_, body := query(t, server, `mutation($id: ID!) { updateUser(id: $id, input: {name: "Ana María"}) { id name email } }`,
+			map[string]interface{}{"id": ana.ID})
+		var user gqlUser
+		field(t, body, "updateUser", &user)
+		assert.Equal(t, "Ana María", user.Name)
+		assert.Equal(t, ana.Email, user.Email)
+	})
+
+	t.Run("errores de la aplicación con su código", func(t *testing.T) {
+		_, body := query(t, server, `mutation($id: ID!) { updateUser(id: $id, input: {name: "x"}) { id } }`,
+			map[string]interface{}{"id": uuid.NewString()})
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "USER_NOT_FOUND", body.Errors[0].Extensions["code"])
+
+		_, body = query(t, server, createUser, map[string]interface{}{"input": map[string]interface{}{
+			"name": "ana", "lastname": "Test", "birthdate": "1990-01-02T00:00:00Z", "email": "no-es-email", "password": "secreto123",
+		}})
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "INVALID_DATA", body.Errors[0].Extensions["code"])
+	})
+
+	t.Run("changePassword", func(t *testing.T) {
+		const changePassword = `mutation($id: ID!, $input: ChangePasswordInput!) { changePassword(id: $id, input: $input) }`
+		// La API key no tiene users.password.reset, así que necesita la contraseña actual
+		_, body := query(t, server, changePassword, map[string]interface{}{"id": ana.ID, "input": map[string]interface{}{"newPassword": "nueva-contraseña"}})
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "CURRENT_PASSWORD_REQUIRED", body.Errors[0].Extensions["code"])
+
+		_, body = query(t, server, changePassword, map[string]interface{}{"id": ana.ID, "input": map[string]interface{}{"currentPassword": "secreto123", "newPassword": "corta"}})
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "PASSWORD_TOO_SHORT", body.Errors[0].Extensions["code"])
+
+		_, body = query(t, server, changePassword, map[string]interface{}{"id": ana.ID, "input": map[string]interface{}{"currentPassword": "secreto123", "newPassword": "nueva-contraseña"}})
+		require.Empty(t, body.Errors)
+		assert.JSONEq(t, "true", string(body.Data["changePassword"]))
+		resp := server.Do(t, http.MethodPost, "/v1/users/login", map[string]string{"email": ana.Email, "password": "nueva-contraseña"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// updateUser ya no acepta la contraseña
+		_, body = query(t, server, `mutation($id: ID!) { updateUser(id: $id, input: {password: "otra-contraseña"}) { id } }`,
+			map[string]interface{}{"id": ana.ID})
+		require.Len(t, body.Errors, 1)
+	})
+
+	t.Run("deleteUser", func(t *testing.T) {
+		_, body := query(t, server, `mutation($id: ID!) { deleteUser(id: $id) }`, map[string]interface{}{"id": ana.ID})
+		require.Empty(t, body.Errors)
+		assert.JSONEq(t, "true", string(body.Data["deleteUser"]))
+
+		_, body = query(t, server, `query($id: ID!) { user(id: $id) { id } }`, map[string]interface{}{"id": ana.ID})
+		assert.JSONEq(t, "null", string(body.Data["user"]))
+	})
+}
+
+func TestLimits(t *testing.T) {
+	server := apptest.NewServer(t, func(cfg *config.Config) {
+		cfg.GraphQLMaxDepth = 3
+		cfg.GraphQLMaxCost = 50
+	})
+
+	t.Run("profundidad", func(t *testing.T) {
+		_, body := query(t, server, `{ users(first: 1) { edges { node { id } } } }`, nil)
+		assert.Nil(t, body.Data)
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_DEEP", body.Errors[0].Extensions["code"])
+
+		_, body = query(t, server, `{ users(first: 1) { totalCount pageInfo { hasNextPage } } }`, nil)
+		assert.Empty(t, body.Errors)
+	})
+
+	t.Run("los fragmentos cuentan para la profundidad", func(t *testing.T) {
+		_, body := query(t, server, `{ users(first: 1) { ...page } } fragment page on UserConnection { edges { node { id } } }`, nil)
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "QUERY_TOO_DEEP", body.Errors[0].Extensions["code"])
+	})
+
+	t.Run("complejidad", func(t *testing.T) {
+		_, body := query(t, server, `{ users(first: 100) { pageInfo { hasNextPage } } }`, nil)
+		assert.Nil(t, body.Data)
+		require.Len(t, body.Errors, 1)
+		assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", body.Errors[0].Extensions["code"])
+	})
+
+	t.Run("la introspección no cuenta para la profundidad", func(t *testing.T) {
+		_, body := query(t, server, `{ __schema { types { name fields { name type { name ofType { name } } } } } }`, nil)
+		assert.Empty(t, body.Errors)
+	})
+
+	t.Run("exige la API key", func(t *testing.T) {
+		resp := server.DoWithKey(t, "", http.MethodPost, "/graphql", map[string]string{"query": "{ users { totalCount } }"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+}
@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"users-api/src/config/log"
+	"users-api/src/errors"
+	"users-api/src/graph/generated"
+	"users-api/src/graph/model"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+)
+
+// Limits acota el costo de una consulta antes de ejecutarla
+type Limits struct {
+	// MaxDepth es la cantidad máxima de niveles de selección anidados, sin contar la introspección
+	MaxDepth int
+	// MaxComplexity es el costo máximo estimado; cada campo cuesta 1 y users multiplica por first
+	MaxComplexity int
+}
+
+// NewHandler arma el handler HTTP de /graphql, con un loader de usuarios nuevo por solicitud
+func NewHandler(resolver *Resolver, limits Limits) http.Handler {
+	cfg := generated.Config{Resolvers: resolver}
+	cfg.Complexity.Query.Users = func(childComplexity int, filter *model.UserFilter, first *int, after *string) int {
+		return 1 + childComplexity*pageSize(first)
+	}
+
+	srv := handler.New(generated.NewExecutableSchema(cfg))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+	srv.Use(depthLimit{max: limits.MaxDepth})
+	srv.Use(extension.FixedComplexityLimit(limits.MaxComplexity))
+	srv.SetErrorPresenter(resolver.presentError)
+	srv.SetRecoverFunc(func(ctx context.Context, err interface{}) error {
+		log.FromContext(ctx, resolver.logger).Error("[USERS-API] Panic en resolver GraphQL", zap.Any("panic", err))
+		return errors.ErrInternalServer
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := withLoader(r.Context(), newUserLoader(resolver.userService))
+		srv.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// presentError expone el código de los errores de la aplicación en extensions.code y oculta el
+// detalle de los errores inesperados, que quedan en el log
+func (r *Resolver) presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var customErr *errors.Error
+	if stderrors.As(err, &customErr) {
+		gqlErr.Message = customErr.Message
+		gqlErr.Extensions = map[string]interface{}{"code": customErr.Code}
+		return gqlErr
+	}
+
+	var original *gqlerror.Error
+	if stderrors.As(err, &original) && original.Err == nil {
+		// Errores de parseo y validación de la consulta generados por gqlgen
+		return gqlErr
+	}
+
+	log.FromContext(ctx, r.logger).Error("[USERS-API] Error en resolver GraphQL", zap.Error(err))
+	gqlErr.Message = errors.ErrInternalServer.Message
+	gqlErr.Extensions = map[string]interface{}{"code": errors.ErrInternalServer.Code}
+	return gqlErr
+}
+
+// depthLimit rechaza las consultas con selecciones anidadas más allá de max niveles
+type depthLimit struct {
+	max int
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = depthLimit{}
+
+func (depthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d depthLimit) Validate(graphql.ExecutableSchema) error {
+	if d.max <= 0 {
+		return fmt.Errorf("la profundidad máxima debe ser mayor a 0")
+	}
+	return nil
+}
+
+func (d depthLimit) MutateOperationContext(ctx context.Context, rc *graphql.OperationContext) *gqlerror.Error {
+	if rc.Operation == nil {
+		return nil
+	}
+	if depth := selectionDepth(rc.Operation.SelectionSet, map[string]bool{}); depth > d.max {
+		err := gqlerror.Errorf("la consulta tiene profundidad %d, el máximo es %d", depth, d.max)
+		err.Extensions = map[string]interface{}{"code": "QUERY_TOO_DEEP"}
+		return err
+	}
+	return nil
+}
+
+// selectionDepth calcula la profundidad siguiendo fragmentos; visiting evita ciclos entre fragmentos
+func selectionDepth(selectionSet ast.SelectionSet, visiting map[string]bool) int {
+	deepest := 0
+	for _, selection := range selectionSet {
+		depth := 0
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			depth = 1 + selectionDepth(s.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = selectionDepth(s.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			if s.Definition == nil || visiting[s.Name] {
+				continue
+			}
+			visiting[s.Name] = true
+			depth = selectionDepth(s.Definition.SelectionSet, visiting)
+			delete(visiting, s.Name)
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+	}
+	return deepest
+}
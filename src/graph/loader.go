@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+	"users-api/src/dto"
+	"users-api/src/services"
+)
+
+const (
+	// loaderWait es cuánto espera el primer pedido de un lote a que lleguen los demás campos
+	loaderWait = 2 * time.Millisecond
+	// loaderMaxBatch despacha el lote antes de loaderWait si ya juntó tantos IDs
+	loaderMaxBatch = 100
+)
+
+// userLoader agrupa las lecturas de usuarios por ID de una misma solicitud en una sola llamada a
+// GetUsersList, así una consulta con varios user(id) no hace una lectura por campo (N+1)
+type userLoader struct {
+	service services.UserService
+	mu      sync.Mutex
+	batch   *userBatch
+}
+
+type userBatch struct {
+	ctx   context.Context
+	ids   []string
+	seen  map[string]bool
+	done  chan struct{}
+	users map[string]*dto.UserResponseDTO
+	err   error
+}
+
+func newUserLoader(service services.UserService) *userLoader {
+	return &userLoader{service: service}
+}
+
+// Load devuelve el usuario con id, o nil si no existe
+func (l *userLoader) Load(ctx context.Context, id string) (*dto.UserResponseDTO, error) {
+	l.mu.Lock()
+	batch := l.batch
+	if batch == nil {
+		batch = &userBatch{ctx: ctx, seen: map[string]bool{}, done: make(chan struct{})}
+		l.batch = batch
+		time.AfterFunc(loaderWait, func() { l.dispatch(batch) })
+	}
+	if !batch.seen[id] {
+		batch.seen[id] = true
+		batch.ids = append(batch.ids, id)
+	}
+	if len(batch.ids) >= loaderMaxBatch {
+		go l.dispatch(batch)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if batch.err != nil {
+		return nil, batch.err
+	}
+	return batch.users[id], nil
+}
+
+// dispatch ejecuta el lote una sola vez, sea por tiempo o por tamaño
+func (l *userLoader) dispatch(batch *userBatch) {
+	l.mu.Lock()
+	if l.batch != batch {
+		l.mu.Unlock()
+		return
+	}
+	l.batch = nil
+	l.mu.Unlock()
+
+	users, err := l.service.GetUsersList(batch.ctx, batch.ids)
+	batch.users = make(map[string]*dto.UserResponseDTO, len(users))
+	for i := range users {
+		batch.users[users[i].ID] = &users[i]
+	}
+	batch.err = err
+	close(batch.done)
+}
+
+type loaderKey struct{}
+
+func withLoader(ctx context.Context, loader *userLoader) context.Context {
+	return context.WithValue(ctx, loaderKey{}, loader)
+}
+
+func loaderFromContext(ctx context.Context) *userLoader {
+	loader, _ := ctx.Value(loaderKey{}).(*userLoader)
+	return loader
+}
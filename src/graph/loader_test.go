@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"users-api/src/dto"
+	"users-api/src/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingService struct {
+	services.UserService
+	calls atomic.Int32
+}
+
+func (s *countingService) GetUsersList(ctx context.Context, ids []string) ([]dto.UserResponseDTO, error) {
+	s.calls.Add(1)
+	users := make([]dto.UserResponseDTO, 0, len(ids))
+	for _, id := range ids {
+		if id != "missing" {
+			users = append(users, dto.UserResponseDTO{ID: id})
+		}
+	}
+	return users, nil
+}
+
+func TestUserLoaderBatches(t *testing.T) {
+	service := &countingService{}
+	loader := newUserLoader(service)
+
+	ids := []string{"a", "b", "a", "missing", "c"}
+	results := make([]*dto.UserResponseDTO, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			user, err := loader.Load(context.Background(), id)
+			require.NoError(t, err)
+			results[i] = user
+		}(i, id)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), service.calls.Load())
+	assert.Equal(t, "a", results[0].ID)
+	assert.Equal(t, "b", results[1].ID)
+	assert.Equal(t, "a", results[2].ID)
+	assert.Nil(t, results[3])
+	assert.Equal(t, "c", results[4].ID)
+
+	// Un lote ya despachado no se reutiliza
+	_, err := loader.Load(context.Background(), "d")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), service.calls.Load())
+}
+
+func TestUserLoaderMaxBatch(t *testing.T) {
+	service := &countingService{}
+	loader := newUserLoader(service)
+
+	var wg sync.WaitGroup
+	for i := 0; i < loaderMaxBatch*2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := loader.Load(context.Background(), string(rune('A'+i%26))+string(rune('0'+i/26)))
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, service.calls.Load(), int32(2))
+}
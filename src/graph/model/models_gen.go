@@ -0,0 +1,37 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"users-api/src/dto"
+)
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+type Query struct {
+}
+
+type UserConnection struct {
+	Edges      []UserEdge `json:"edges"`
+	PageInfo   *PageInfo  `json:"pageInfo"`
+	TotalCount int        `json:"totalCount"`
+}
+
+type UserEdge struct {
+	Cursor string               `json:"cursor"`
+	Node   *dto.UserResponseDTO `json:"node"`
+}
+
+// Filtro de users, todos los campos presentes deben coincidir exactamente
+type UserFilter struct {
+	Name     *string `json:"name,omitempty"`
+	Lastname *string `json:"lastname,omitempty"`
+	Role     *string `json:"role,omitempty"`
+	Email    *string `json:"email,omitempty"`
+}
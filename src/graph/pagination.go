@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/graph/model"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+var errInvalidCursor = errors.NewError("INVALID_CURSOR", "El cursor after no es válido", http.StatusBadRequest)
+
+// pageSize normaliza first al rango [1, maxPageSize]; también lo usa el cálculo de complejidad
+func pageSize(first *int) int {
+	if first == nil {
+		return defaultPageSize
+	}
+	if *first < 1 {
+		return 1
+	}
+	if *first > maxPageSize {
+		return maxPageSize
+	}
+	return *first
+}
+
+func encodeCursor(id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	id, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(id) == 0 {
+		return "", errInvalidCursor
+	}
+	return string(id), nil
+}
+
+// userFilter traduce el filtro de GraphQL al de UserService.GetAllUsers, el mismo que usa REST
+func userFilter(filter *model.UserFilter) map[string]interface{} {
+	values := map[string]interface{}{}
+	if filter == nil {
+		return values
+	}
+	for key, value := range map[string]*string{
+		"name":     filter.Name,
+		"lastname": filter.Lastname,
+		"role":     filter.Role,
+		"email":    filter.Email,
+	} {
+		if value != nil {
+			values[key] = *value
+		}
+	}
+	return values
+}
+
+// paginate ordena por ID y devuelve la página de first usuarios posteriores al cursor after
+func paginate(users []dto.UserResponseDTO, first *int, after *string) (*model.UserConnection, error) {
+	var afterID string
+	if after != nil && *after != "" {
+		var err error
+		if afterID, err = decodeCursor(*after); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	start := sort.Search(len(users), func(i int) bool { return users[i].ID > afterID })
+	end := start + pageSize(first)
+	if end > len(users) {
+		end = len(users)
+	}
+
+	connection := &model.UserConnection{
+		Edges:      make([]model.UserEdge, 0, end-start),
+		PageInfo:   &model.PageInfo{HasNextPage: end < len(users)},
+		TotalCount: len(users),
+	}
+	for i := start; i < end; i++ {
+		connection.Edges = append(connection.Edges, model.UserEdge{Cursor: encodeCursor(users[i].ID), Node: &users[i]})
+	}
+	if len(connection.Edges) > 0 {
+		endCursor := connection.Edges[len(connection.Edges)-1].Cursor
+		connection.PageInfo.EndCursor = &endCursor
+	}
+	return connection, nil
+}
@@ -0,0 +1,47 @@
+// Package graph implementa /graphql sobre services.UserService. El esquema está en schema.graphqls
+// y el código de ejecución en generated/ lo genera gqlgen.
+package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+import (
+	"context"
+	stderrors "errors"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type Resolver struct {
+	userService services.UserService
+	logger      *zap.Logger
+}
+
+func NewResolver(userService services.UserService, logger *zap.Logger) *Resolver {
+	return &Resolver{
+		userService: userService,
+		logger:      logger,
+	}
+}
+
+// nullIfNotFound convierte un usuario inexistente en null, como se espera de un campo nullable
+func nullIfNotFound(user *dto.UserResponseDTO, err error) (*dto.UserResponseDTO, error) {
+	if stderrors.Is(err, gorm.ErrRecordNotFound) || stderrors.Is(err, errors.ErrUserNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+// validate aplica las mismas validaciones que gin al bindear el JSON en POST /v1/users
+func (r *Resolver) validate(ctx context.Context, input interface{}) error {
+	if err := binding.Validator.ValidateStruct(input); err != nil {
+		log.FromContext(ctx, r.logger).Warn("[USERS-API]: Datos de usuario inválidos", zap.Error(err))
+		return errors.ErrInvalidData
+	}
+	return nil
+}
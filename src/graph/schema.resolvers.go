@@ -0,0 +1,75 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.49
+
+import (
+	"context"
+	"users-api/src/dto"
+	"users-api/src/graph/generated"
+	"users-api/src/graph/model"
+)
+
+// CreateUser is the resolver for the createUser field.
+func (r *mutationResolver) CreateUser(ctx context.Context, input dto.CreateUserDTO) (*dto.UserResponseDTO, error) {
+	if err := r.validate(ctx, &input); err != nil {
+		return nil, err
+	}
+	return r.userService.CreateUser(ctx, &input)
+}
+
+// UpdateUser is the resolver for the updateUser field.
+func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input dto.UpdateUserDTO) (*dto.UserResponseDTO, error) {
+	return r.userService.UpdateUser(ctx, id, &input)
+}
+
+// DeleteUser is the resolver for the deleteUser field.
+func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (bool, error) {
+	if err := r.userService.DeleteUser(ctx, id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ChangePassword is the resolver for the changePassword field.
+func (r *mutationResolver) ChangePassword(ctx context.Context, id string, input dto.ChangePasswordDTO) (bool, error) {
+	if err := r.validate(ctx, &input); err != nil {
+		return false, err
+	}
+	if err := r.userService.ChangePassword(ctx, id, &input); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*dto.UserResponseDTO, error) {
+	if loader := loaderFromContext(ctx); loader != nil {
+		return loader.Load(ctx, id)
+	}
+	return nullIfNotFound(r.userService.GetUserByID(ctx, id))
+}
+
+// UserByEmail is the resolver for the userByEmail field.
+func (r *queryResolver) UserByEmail(ctx context.Context, email string) (*dto.UserResponseDTO, error) {
+	return nullIfNotFound(r.userService.GetUserByEmail(ctx, email))
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, filter *model.UserFilter, first *int, after *string) (*model.UserConnection, error) {
+	users, err := r.userService.GetAllUsers(ctx, userFilter(filter))
+	if err != nil {
+		return nil, err
+	}
+	return paginate(users, first, after)
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
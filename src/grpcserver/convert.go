@@ -0,0 +1,57 @@
+package grpcserver
+
+import (
+	"users-api/src/dto"
+	"users-api/src/grpcserver/userspb"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toPBUser(user *dto.UserResponseDTO) *userspb.User {
+	return &userspb.User{
+		Id:        user.ID,
+		Name:      user.Name,
+		Lastname:  user.Lastname,
+		Birthdate: timestamppb.New(user.Birthdate),
+		Role:      user.Role,
+		Email:     user.Email,
+		Avatar:    user.Avatar,
+	}
+}
+
+func toCreateUserDTO(req *userspb.CreateUserRequest) *dto.CreateUserDTO {
+	createUserDTO := &dto.CreateUserDTO{
+		Name:     req.GetName(),
+		Lastname: req.GetLastname(),
+		Role:     req.GetRole(),
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+		Avatar:   req.GetAvatar(),
+	}
+	if req.GetBirthdate() != nil {
+		createUserDTO.Birthdate = req.GetBirthdate().AsTime()
+	}
+	return createUserDTO
+}
+
+func toUpdateUserDTO(req *userspb.UpdateUserRequest) *dto.UpdateUserDTO {
+	updateUserDTO := &dto.UpdateUserDTO{
+		Name:     req.Name,
+		Lastname: req.Lastname,
+		Role:     req.Role,
+		Email:    req.Email,
+		Avatar:   req.Avatar,
+	}
+	if req.GetBirthdate() != nil {
+		birthdate := req.GetBirthdate().AsTime()
+		updateUserDTO.Birthdate = &birthdate
+	}
+	return updateUserDTO
+}
+
+func toChangePasswordDTO(req *userspb.ChangePasswordRequest) *dto.ChangePasswordDTO {
+	return &dto.ChangePasswordDTO{
+		CurrentPassword: req.GetCurrentPassword(),
+		NewPassword:     req.GetNewPassword(),
+	}
+}
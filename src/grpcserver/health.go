@@ -0,0 +1,36 @@
+package grpcserver
+
+import (
+	"context"
+	"users-api/src/grpcserver/userspb"
+	"users-api/src/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthServer responde grpc.health.v1 con las mismas verificaciones que /readyz
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	service services.HealthService
+}
+
+func newHealthServer(service services.HealthService) *healthServer {
+	return &healthServer{service: service}
+}
+
+// Check acepta el servicio vacío (el servidor completo) y users.v1.UsersService
+func (h *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	switch req.GetService() {
+	case "", userspb.UsersService_ServiceDesc.ServiceName:
+	default:
+		return nil, status.Error(codes.NotFound, "servicio desconocido")
+	}
+
+	resp := &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}
+	if h.service.Readiness(ctx).Status == services.HealthStatusFail {
+		resp.Status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+	}
+	return resp, nil
+}
@@ -0,0 +1,143 @@
+package grpcserver
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+	"users-api/src/auth"
+	"users-api/src/config/log"
+	"users-api/src/errors"
+	"users-api/src/metrics"
+	"users-api/src/services"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDKey es el equivalente en metadata del encabezado X-Request-ID de la API REST
+const requestIDKey = "x-request-id"
+
+// maxRequestIDLength evita que un cliente inyecte valores enormes en los logs
+const maxRequestIDLength = 128
+
+// publicServices no exigen credenciales para que los probes y grpcurl funcionen sin la API key
+var publicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+func recoveryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.FromContext(ctx, logger).Error("[USERS-API] Panic en llamada gRPC",
+					zap.String("method", info.FullMethod), zap.Any("panic", r), zap.ByteString("stack", debug.Stack()))
+				err = status.Error(codes.Internal, errors.ErrInternalServer.Message)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// requestIDInterceptor acepta o genera el x-request-id, lo devuelve en el header de la respuesta y
+// deja en el contexto el mismo logger con request_id que arma RequestIDMiddleware en HTTP
+func requestIDInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var requestID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestIDKey); len(values) > 0 && validRequestID(values[0]) {
+				requestID = values[0]
+			}
+		}
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+		fields := []zap.Field{
+			zap.String("request_id", requestID),
+			zap.String("method", info.FullMethod),
+		}
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+			fields = append(fields, zap.String("trace_id", spanContext.TraceID().String()))
+		}
+
+		ctx = log.WithRequestID(ctx, requestID)
+		ctx = log.WithLogger(ctx, logger.With(fields...))
+		return handler(ctx, req)
+	}
+}
+
+func loggingInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		duration := time.Since(start)
+
+		code := status.Code(err)
+		metrics.GRPCRequests.WithLabelValues(info.FullMethod, code.String()).Inc()
+		metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code.String()).Observe(duration.Seconds())
+
+		fields := []zap.Field{
+			zap.String("code", code.String()),
+			zap.Duration("duration", duration),
+		}
+		requestLogger := log.FromContext(ctx, logger)
+		switch code {
+		case codes.OK:
+			requestLogger.Info("[USERS-API] Llamada gRPC procesada", fields...)
+		case codes.Internal, codes.Unknown, codes.Unavailable:
+			requestLogger.Error("[USERS-API] Error en la llamada gRPC", append(fields, zap.Error(err))...)
+		default:
+			requestLogger.Warn("[USERS-API] Llamada gRPC rechazada", append(fields, zap.Error(err))...)
+		}
+		return resp, err
+	}
+}
+
+// authInterceptor acepta en el metadata authorization la API key, igual que la API REST, o
+// "Bearer <token>" con un token de acceso emitido por Authenticate. Los permisos los verifican los servicios
+func authInterceptor(credentials services.CredentialService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		for _, prefix := range publicServices {
+			if strings.HasPrefix(info.FullMethod, prefix) {
+				return handler(ctx, req)
+			}
+		}
+
+		var credential string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				credential = values[0]
+			}
+		}
+
+		principal, err := credentials.Authenticate(ctx, credential)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		if principal.UserID != "" {
+			ctx = log.WithLogger(ctx, log.FromContext(ctx, zap.NewNop()).With(zap.String("user_id", principal.UserID)))
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+// Package grpcserver expone users.v1.UsersService sobre los mismos servicios que la API REST,
+// para que los servicios internos llamen con stubs generados desde proto/users/v1/users.proto.
+package grpcserver
+
+import (
+	"context"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/grpcserver/userspb"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type UsersServer struct {
+	userspb.UnimplementedUsersServiceServer
+	userService  services.UserService
+	authService  services.AuthService
+	tokenService services.TokenService
+	logger       *zap.Logger
+}
+
+func NewUsersServer(userService services.UserService, authService services.AuthService, tokenService services.TokenService, logger *zap.Logger) *UsersServer {
+	return &UsersServer{
+		userService:  userService,
+		authService:  authService,
+		tokenService: tokenService,
+		logger:       logger,
+	}
+}
+
+// NewServer arma el servidor gRPC con los interceptores de request ID, logs, métricas y autenticación,
+// el servicio de salud estándar y reflection para grpcurl
+func NewServer(credentials services.CredentialService, healthService services.HealthService, users *UsersServer, logger *zap.Logger) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			recoveryInterceptor(logger),
+			requestIDInterceptor(logger),
+			loggingInterceptor(logger),
+			authInterceptor(credentials),
+		),
+	)
+
+	userspb.RegisterUsersServiceServer(server, users)
+	grpc_health_v1.RegisterHealthServer(server, newHealthServer(healthService))
+	reflection.Register(server)
+
+	return server
+}
+
+func (s *UsersServer) GetUser(ctx context.Context, req *userspb.GetUserRequest) (*userspb.User, error) {
+	user, err := s.userService.GetUserByID(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+func (s *UsersServer) BatchGetUsers(ctx context.Context, req *userspb.BatchGetUsersRequest) (*userspb.BatchGetUsersResponse, error) {
+	if len(req.GetIds()) == 0 {
+		return nil, toStatus(errors.NewError("INVALID_DATA", "La lista de IDs no puede estar vacía", errors.ErrInvalidData.HTTPStatusCode))
+	}
+
+	users, err := s.userService.GetUsersList(ctx, req.GetIds())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &userspb.BatchGetUsersResponse{Users: make([]*userspb.User, 0, len(users))}
+	for i := range users {
+		resp.Users = append(resp.Users, toPBUser(&users[i]))
+	}
+	return resp, nil
+}
+
+func (s *UsersServer) GetUserByEmail(ctx context.Context, req *userspb.GetUserByEmailRequest) (*userspb.User, error) {
+	user, err := s.userService.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+func (s *UsersServer) CreateUser(ctx context.Context, req *userspb.CreateUserRequest) (*userspb.User, error) {
+	createUserDTO := toCreateUserDTO(req)
+	// Las mismas validaciones que aplica gin al bindear el JSON en POST /v1/users
+	if err := binding.Validator.ValidateStruct(createUserDTO); err != nil {
+		log.FromContext(ctx, s.logger).Warn("[USERS-API]: Datos de usuario inválidos", zap.Error(err))
+		return nil, toStatus(errors.ErrInvalidData)
+	}
+
+	user, err := s.userService.CreateUser(ctx, createUserDTO)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+func (s *UsersServer) UpdateUser(ctx context.Context, req *userspb.UpdateUserRequest) (*userspb.User, error) {
+	user, err := s.userService.UpdateUser(ctx, req.GetId(), toUpdateUserDTO(req))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toPBUser(user), nil
+}
+
+func (s *UsersServer) DeleteUser(ctx context.Context, req *userspb.DeleteUserRequest) (*emptypb.Empty, error) {
+	if err := s.userService.DeleteUser(ctx, req.GetId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UsersServer) ChangePassword(ctx context.Context, req *userspb.ChangePasswordRequest) (*emptypb.Empty, error) {
+	if err := s.userService.ChangePassword(ctx, req.GetId(), toChangePasswordDTO(req)); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *UsersServer) Authenticate(ctx context.Context, req *userspb.AuthenticateRequest) (*userspb.AuthenticateResponse, error) {
+	user, err := s.authService.Login(ctx, &dto.LoginDTO{Email: req.GetEmail(), Password: req.GetPassword()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &userspb.AuthenticateResponse{User: toPBUser(user)}
+	if s.tokenService.Enabled() {
+		token, expiresAt, err := s.tokenService.Issue(ctx, user)
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		resp.AccessToken = token
+		resp.ExpiresAt = timestamppb.New(expiresAt)
+	}
+	return resp, nil
+}
@@ -0,0 +1,234 @@
+package grpcserver_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+	"users-api/src/apptest"
+	"users-api/src/grpcserver/userspb"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dial levanta el servidor gRPC de la aplicación sobre una conexión en memoria
+func dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	server := apptest.NewServer(t)
+	grpcServer := server.App.GetGRPCServer()
+	require.NotNil(t, grpcServer)
+
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func withCredential(credential string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", credential)
+}
+
+func newCreateUserRequest(name string) *userspb.CreateUserRequest {
+	return &userspb.CreateUserRequest{
+		Name:      name,
+		Lastname:  "Test",
+		Birthdate: timestamppb.New(time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)),
+		Email:     name + "-" + uuid.NewString()[:8] + "@example.com",
+		Password:  "secreto123",
+	}
+}
+
+func requireCode(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+	st, ok := status.FromError(err)
+	require.True(t, ok, "no es un status gRPC: %v", err)
+	require.Equal(t, code, st.Code(), st.Message())
+	if reason == "" {
+		return
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			assert.Equal(t, reason, info.Reason)
+			return
+		}
+	}
+	t.Errorf("el status no tiene ErrorInfo con reason %s", reason)
+}
+
+func TestUsersService(t *testing.T) {
+	client := userspb.NewUsersServiceClient(dial(t))
+	ctx := withCredential(apptest.APIKey)
+
+	req := newCreateUserRequest("ana")
+	var header metadata.MD
+	ana, err := client.CreateUser(ctx, req, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.NotEmpty(t, ana.GetId())
+	assert.Equal(t, "user", ana.GetRole())
+	assert.NotEmpty(t, header.Get("x-request-id"))
+
+	t.Run("GetUser y GetUserByEmail", func(t *testing.T) {
+		user, err := client.GetUser(ctx, &userspb.GetUserRequest{Id: ana.GetId()})
+		require.NoError(t, err)
+		assert.Equal(t, ana.GetEmail(), user.GetEmail())
+		assert.True(t, req.GetBirthdate().AsTime().Equal(user.GetBirthdate().AsTime()))
+
+		user, err = client.GetUserByEmail(ctx, &userspb.GetUserByEmailRequest{Email: ana.GetEmail()})
+		require.NoError(t, err)
+		assert.Equal(t, ana.GetId(), user.GetId())
+	})
+
+	t.Run("BatchGetUsers omite los inexistentes", func(t *testing.T) {
+		beto, err := client.CreateUser(ctx, newCreateUserRequest("beto"))
+		require.NoError(t, err)
+
+		resp, err := client.BatchGetUsers(ctx, &userspb.BatchGetUsersRequest{Ids: []string{ana.GetId(), beto.GetId(), uuid.NewString()}})
+		require.NoError(t, err)
+		assert.Len(t, resp.GetUsers(), 2)
+
+		_, err = client.BatchGetUsers(ctx, &userspb.BatchGetUsersRequest{})
+		requireCode(t, err, codes.InvalidArgument, "INVALID_DATA")
+	})
+
+	t.Run("UpdateUser modifica solo los campos presentes", func(t *testing.T) {
+		name := "Ana María"
+		user, err := client.UpdateUser(ctx, &userspb.UpdateUserRequest{Id: ana.GetId(), Name: &name})
+		require.NoError(t, err)
+		assert.Equal(t, name, user.GetName())
+		assert.Equal(t, ana.GetLastname(), user.GetLastname())
+	})
+
+	t.Run("errores de validación y not found", func(t *testing.T) {
+		_, err := client.CreateUser(ctx, &userspb.CreateUserRequest{Name: "sin email"})
+		requireCode(t, err, codes.InvalidArgument, "INVALID_DATA")
+
+		_, err = client.GetUser(ctx, &userspb.GetUserRequest{Id: uuid.NewString()})
+		requireCode(t, err, codes.NotFound, "USER_NOT_FOUND")
+
+		_, err = client.DeleteUser(ctx, &userspb.DeleteUserRequest{Id: uuid.NewString()})
+		requireCode(t, err, codes.NotFound, "USER_NOT_FOUND")
+	})
+
+	t.Run("DeleteUser", func(t *testing.T) {
+		beto, err := client.CreateUser(ctx, newCreateUserRequest("beto"))
+		require.NoError(t, err)
+
+		_, err = client.DeleteUser(ctx, &userspb.DeleteUserRequest{Id: beto.GetId()})
+		require.NoError(t, err)
+		_, err = client.GetUser(ctx, &userspb.GetUserRequest{Id: beto.GetId()})
+		requireCode(t, err, codes.NotFound, "USER_NOT_FOUND")
+	})
+}
+
+func TestAuthentication(t *testing.T) {
+	client := userspb.NewUsersServiceClient(dial(t))
+	ctx := withCredential(apptest.APIKey)
+
+	req := newCreateUserRequest("ana")
+	ana, err := client.CreateUser(ctx, req)
+	require.NoError(t, err)
+	beto, err := client.CreateUser(ctx, newCreateUserRequest("beto"))
+	require.NoError(t, err)
+
+	t.Run("sin credenciales o con una API key inválida", func(t *testing.T) {
+		_, err := client.GetUser(context.Background(), &userspb.GetUserRequest{Id: ana.GetId()})
+		requireCode(t, err, codes.Unauthenticated, "")
+
+		_, err = client.GetUser(withCredential("otra-clave"), &userspb.GetUserRequest{Id: ana.GetId()})
+		requireCode(t, err, codes.Unauthenticated, "")
+	})
+
+	t.Run("Authenticate con credenciales inválidas", func(t *testing.T) {
+		_, err := client.Authenticate(ctx, &userspb.AuthenticateRequest{Email: req.GetEmail(), Password: "incorrecta"})
+		requireCode(t, err, codes.Unauthenticated, "INVALID CREDENTIALS")
+	})
+
+	resp, err := client.Authenticate(ctx, &userspb.AuthenticateRequest{Email: req.GetEmail(), Password: req.GetPassword()})
+	require.NoError(t, err)
+	assert.Equal(t, ana.GetId(), resp.GetUser().GetId())
+	require.NotEmpty(t, resp.GetAccessToken())
+	assert.True(t, resp.GetExpiresAt().AsTime().After(time.Now()))
+	userCtx := withCredential("Bearer " + resp.GetAccessToken())
+
+	t.Run("el token permite operar sobre la propia cuenta", func(t *testing.T) {
+		user, err := client.GetUser(userCtx, &userspb.GetUserRequest{Id: ana.GetId()})
+		require.NoError(t, err)
+		assert.Equal(t, ana.GetEmail(), user.GetEmail())
+
+		avatar := "https://example.com/ana.png"
+		_, err = client.UpdateUser(userCtx, &userspb.UpdateUserRequest{Id: ana.GetId(), Avatar: &avatar})
+		require.NoError(t, err)
+	})
+
+	t.Run("el token no permite operar sobre otros usuarios ni operaciones de servicio", func(t *testing.T) {
+		_, err := client.GetUser(userCtx, &userspb.GetUserRequest{Id: beto.GetId()})
+		requireCode(t, err, codes.PermissionDenied, "FORBIDDEN")
+
+		_, err = client.DeleteUser(userCtx, &userspb.DeleteUserRequest{Id: beto.GetId()})
+		requireCode(t, err, codes.PermissionDenied, "FORBIDDEN")
+
+		_, err = client.BatchGetUsers(userCtx, &userspb.BatchGetUsersRequest{Ids: []string{ana.GetId()}})
+		requireCode(t, err, codes.PermissionDenied, "FORBIDDEN")
+	})
+
+	t.Run("ni la API key ni el token pueden asignar roles", func(t *testing.T) {
+		admin := newCreateUserRequest("admin")
+		admin.Role = "admin"
+		_, err := client.CreateUser(ctx, admin)
+		requireCode(t, err, codes.PermissionDenied, "FORBIDDEN")
+
+		role := "admin"
+		_, err = client.UpdateUser(userCtx, &userspb.UpdateUserRequest{Id: ana.GetId(), Role: &role})
+		requireCode(t, err, codes.PermissionDenied, "FORBIDDEN")
+	})
+
+	t.Run("un token inválido se rechaza", func(t *testing.T) {
+		_, err := client.GetUser(withCredential("Bearer "+resp.GetAccessToken()+"x"), &userspb.GetUserRequest{Id: ana.GetId()})
+		requireCode(t, err, codes.Unauthenticated, "INVALID_TOKEN")
+	})
+
+	t.Run("ChangePassword pide la contraseña actual y revoca el token", func(t *testing.T) {
+		_, err := client.ChangePassword(userCtx, &userspb.ChangePasswordRequest{Id: ana.GetId(), NewPassword: "nueva-contraseña"})
+		requireCode(t, err, codes.InvalidArgument, "")
+
+		_, err = client.ChangePassword(userCtx, &userspb.ChangePasswordRequest{
+			Id: ana.GetId(), CurrentPassword: req.GetPassword(), NewPassword: "nueva-contraseña",
+		})
+		require.NoError(t, err)
+
+		_, err = client.GetUser(userCtx, &userspb.GetUserRequest{Id: ana.GetId()})
+		requireCode(t, err, codes.Unauthenticated, "")
+		_, err = client.Authenticate(ctx, &userspb.AuthenticateRequest{Email: req.GetEmail(), Password: "nueva-contraseña"})
+		require.NoError(t, err)
+	})
+}
+
+func TestHealth(t *testing.T) {
+	client := grpc_health_v1.NewHealthClient(dial(t))
+
+	for _, service := range []string{"", userspb.UsersService_ServiceDesc.ServiceName} {
+		resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+		require.NoError(t, err)
+		assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.GetStatus())
+	}
+
+	_, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "otro.Service"})
+	requireCode(t, err, codes.NotFound, "")
+}
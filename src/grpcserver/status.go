@@ -0,0 +1,55 @@
+package grpcserver
+
+import (
+	stderrors "errors"
+	"net/http"
+	"users-api/src/errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const errorDomain = "users-api"
+
+var httpToCode = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusInternalServerError: codes.Internal,
+}
+
+// toStatus traduce los errores de la aplicación al código gRPC equivalente al status HTTP, con el
+// código de la aplicación (USER_NOT_FOUND, ...) como reason de un ErrorInfo
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		err = errors.ErrUserNotFound
+	}
+
+	customErr, ok := err.(*errors.Error)
+	if !ok {
+		customErr = errors.ErrInternalServer
+	}
+
+	code, ok := httpToCode[customErr.HTTPStatusCode]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, customErr.Message)
+	if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: customErr.Code, Domain: errorDomain}); detailErr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
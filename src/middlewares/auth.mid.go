@@ -0,0 +1,58 @@
+package middlewares
+
+import (
+	"net/http"
+	"users-api/src/auth"
+	"users-api/src/config/log"
+	"users-api/src/errors"
+	"users-api/src/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuthMiddleware autentica el encabezado Authorization, con la API key o "Bearer <token>",
+// y deja el principal en el contexto de la solicitud para que los servicios verifiquen permisos
+func AuthMiddleware(credentials services.CredentialService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		principal, err := credentials.Authenticate(ctx, c.GetHeader("Authorization"))
+		if err != nil {
+			if customErr, ok := err.(*errors.Error); ok {
+				ErrorResponse(c, customErr.HTTPStatusCode, customErr.Message)
+				return
+			}
+			log.FromContext(ctx, zap.NewNop()).Error("[USERS-API] Error al autenticar la solicitud", zap.Error(err))
+			ErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer.Message)
+			return
+		}
+
+		if principal.UserID != "" {
+			ctx = log.WithLogger(ctx, log.FromContext(ctx, zap.NewNop()).With(zap.String("user_id", principal.UserID)))
+		}
+		c.Request = c.Request.WithContext(auth.WithPrincipal(ctx, principal))
+		c.Next()
+	}
+}
+
+// RequirePermission rechaza con 403 las solicitudes cuyo principal no tiene permission. Va después de AuthMiddleware
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.FromContext(c.Request.Context()).Can(permission) {
+			ErrorResponse(c, errors.ErrForbidden.HTTPStatusCode, errors.ErrForbidden.Message)
+			return
+		}
+		c.Next()
+	}
+}
+
+// ErrorResponse sets CORS headers and aborts the request with a JSON error response
+func ErrorResponse(c *gin.Context, status int, message string) {
+	c.Header("Access-Control-Allow-Origin", c.Request.Header.Get("Origin"))
+	c.Header("Access-Control-Allow-Credentials", "true")
+	body := gin.H{"error": message}
+	if requestID := log.RequestIDFromContext(c.Request.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+	c.AbortWithStatusJSON(status, body)
+}
@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"users-api/src/config/log"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeprecationMiddleware marca las respuestas de rutas deprecadas con los encabezados Deprecation (RFC 9745)
+// y Sunset (RFC 8594), y con un Link a la misma ruta bajo successorPrefix
+func DeprecationMiddleware(deprecatedAt, sunset time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", "<"+successorPrefix+c.Request.URL.Path+`>; rel="successor-version"`)
+
+		logger := log.FromContext(c.Request.Context(), zap.NewNop())
+		logger.Info("[USERS-API] Uso de ruta deprecada", zap.String("successor", successorPrefix+c.Request.URL.Path), zap.String("user-agent", c.Request.UserAgent()))
+
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"users-api/src/config/log"
 	"users-api/src/errors"
 
 	"github.com/gin-gonic/gin"
@@ -13,13 +14,15 @@ func ErrorHandlerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last().Err
+			requestLogger := log.FromContext(c.Request.Context(), logger)
+			requestID := log.RequestIDFromContext(c.Request.Context())
 
 			if customErr, ok := err.(*errors.Error); ok {
-				logger.Error("Error de aplicación", zap.Error(customErr))
-				c.JSON(customErr.HTTPStatusCode, gin.H{"error": customErr.Message})
+				requestLogger.Error("Error de aplicación", zap.Error(customErr))
+				c.JSON(customErr.HTTPStatusCode, gin.H{"error": customErr.Message, "request_id": requestID})
 			} else {
-				logger.Error("Error no manejado", zap.Error(err))
-				c.JSON(errors.ErrInternalServer.HTTPStatusCode, gin.H{"error": errors.ErrInternalServer.Message})
+				requestLogger.Error("Error no manejado", zap.Error(err))
+				c.JSON(errors.ErrInternalServer.HTTPStatusCode, gin.H{"error": errors.ErrInternalServer.Message, "request_id": requestID})
 			}
 		}
 	}
@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"time"
+	"users-api/src/config/log"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -14,10 +15,10 @@ func LoggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 		c.Next()
 
 		duration := time.Since(start)
+		requestLogger := log.FromContext(c.Request.Context(), logger)
 
 		fields := []zap.Field{
 			zap.Int("status", c.Writer.Status()),
-			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("query", c.Request.URL.RawQuery),
 			zap.String("ip", c.ClientIP()),
@@ -27,10 +28,10 @@ func LoggerMiddleware(logger *zap.Logger) gin.HandlerFunc {
 
 		if len(c.Errors) > 0 {
 			for _, e := range c.Errors {
-				logger.Error("[USERS-API] Error en la solicitud", append(fields, zap.Error(e.Err))...)
+				requestLogger.Error("[USERS-API] Error en la solicitud", append(fields, zap.Error(e.Err))...)
 			}
 		} else {
-			logger.Info("[USERS-API] Solicitud procesada", fields...)
+			requestLogger.Info("[USERS-API] Solicitud procesada", fields...)
 		}
 	}
 }
@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+	"users-api/src/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware registra cantidad y duración de solicitudes por plantilla de ruta,
+// así /users/:id no genera una serie por cada ID
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
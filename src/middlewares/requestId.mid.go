@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"users-api/src/config/log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength evita que un cliente inyecte valores enormes en los logs
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware acepta o genera el X-Request-ID, lo devuelve en la respuesta y deja en el
+// contexto un logger con el ID de la solicitud, la ruta y el usuario para que lo usen todas las capas
+func RequestIDMiddleware(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		fields := []zap.Field{
+			zap.String("request_id", requestID),
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+		}
+		if userID := c.Param("id"); userID != "" {
+			fields = append(fields, zap.String("user_id", userID))
+		}
+		if spanContext := trace.SpanContextFromContext(c.Request.Context()); spanContext.HasTraceID() {
+			fields = append(fields, zap.String("trace_id", spanContext.TraceID().String()))
+		}
+
+		ctx := log.WithRequestID(c.Request.Context(), requestID)
+		ctx = log.WithLogger(ctx, logger.With(fields...))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Group agrupa usuarios de una organización, por ejemplo una cohorte de un curso. El nombre es único por organización
+type Group struct {
+	ID          string `gorm:"primaryKey"`
+	TenantID    string `gorm:"not null;uniqueIndex:idx_groups_tenant_name,priority:1"`
+	Name        string `gorm:"not null;uniqueIndex:idx_groups_tenant_name,priority:2"`
+	Description string
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// GroupMember es la pertenencia de un usuario a un grupo de su misma organización
+type GroupMember struct {
+	GroupID   string    `gorm:"primaryKey"`
+	UserID    string    `gorm:"primaryKey;index"`
+	TenantID  string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Invitation es la invitación a crear una cuenta en la organización TenantID con Email y Role. Está
+// pendiente mientras no se haya aceptado ni revocado y no haya vencido
+type Invitation struct {
+	ID         string    `gorm:"primaryKey"`
+	TenantID   string    `gorm:"not null;index:idx_invitations_tenant_email,priority:1"`
+	Email      string    `gorm:"not null;index:idx_invitations_tenant_email,priority:2"`
+	Role       string    `gorm:"not null"`
+	InvitedBy  string    `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"not null"`
+	AcceptedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+// Pending indica si la invitación todavía se puede aceptar en now
+func (i *Invitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
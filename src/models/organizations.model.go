@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DefaultOrganizationID es la organización de los usuarios creados antes de introducir tenants
+// y la que corresponde a USERS_API_KEY
+const DefaultOrganizationID = "default"
+
+// Organization es un tenant: una escuela con sus propios usuarios y su propia API key
+type Organization struct {
+	ID   string `gorm:"primaryKey"`
+	Name string `gorm:"not null"`
+	// APIKeyHash es el SHA-256 de la API key de la organización, la clave en sí no se guarda
+	APIKeyHash *string   `gorm:"uniqueIndex"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
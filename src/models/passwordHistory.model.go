@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// PasswordHistory es un hash de una contraseña anterior del usuario UserID, para no permitir que la
+// vuelva a usar mientras esté entre sus últimas contraseñas
+type PasswordHistory struct {
+	ID        string    `gorm:"primaryKey"`
+	TenantID  string    `gorm:"not null"`
+	UserID    string    `gorm:"not null;index:idx_password_history_user_created,priority:1"`
+	Password  string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null;index:idx_password_history_user_created,priority:2"`
+}
+
+// TableName mantiene el nombre en singular, igual que en la migración
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
@@ -0,0 +1,12 @@
+package models
+
+type Permission struct {
+	Name        string `gorm:"primaryKey"`
+	Description string `gorm:"not null"`
+}
+
+type Role struct {
+	Name        string       `gorm:"primaryKey"`
+	Description string       `gorm:"not null"`
+	Permissions []Permission `gorm:"many2many:role_permissions;joinForeignKey:RoleName;joinReferences:PermissionName"`
+}
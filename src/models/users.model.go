@@ -1,21 +1,59 @@
 package models
 
 import (
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// Estados de una cuenta. Solo las activas pueden iniciar sesión y usar sus tokens
+const (
+	UserStatusPending   = "pending"
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDisabled  = "disabled"
+)
+
+// userStatusTransitions son los cambios de estado permitidos. Deshabilitar es definitivo
+var userStatusTransitions = map[string][]string{
+	UserStatusPending:   {UserStatusActive, UserStatusDisabled},
+	UserStatusActive:    {UserStatusSuspended, UserStatusDisabled},
+	UserStatusSuspended: {UserStatusActive, UserStatusDisabled},
+}
+
+// IsUserStatus indica si status es uno de los estados definidos
+func IsUserStatus(status string) bool {
+	switch status {
+	case UserStatusPending, UserStatusActive, UserStatusSuspended, UserStatusDisabled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionUserStatus indica si una cuenta puede pasar del estado from a to
+func CanTransitionUserStatus(from, to string) bool {
+	return slices.Contains(userStatusTransitions[from], to)
+}
+
+// User pertenece a la organización TenantID. El email es único dentro de cada organización
 type User struct {
 	ID        string    `gorm:"primaryKey"`
+	TenantID  string    `gorm:"not null;default:default;uniqueIndex:idx_users_tenant_email,priority:1;index:idx_users_tenant_status,priority:1"`
 	Name      string    `gorm:"not null"`
 	Lastname  string    `gorm:"not null"`
 	Birthdate time.Time `gorm:"not null"`
 	Role      string    `gorm:"not null"`
-	Email     string    `gorm:"uniqueIndex;not null"`
+	Email     string    `gorm:"uniqueIndex:idx_users_tenant_email,priority:2;not null"`
 	Password  string    `gorm:"not null"`
 	Avatar    string
-	CreatedAt time.Time      `gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Status vacío, como en las entradas de caché anteriores a los estados, equivale a activa
+	Status          string `gorm:"not null;default:active;index:idx_users_tenant_status,priority:2"`
+	StatusReason    string `gorm:"not null;default:''"`
+	StatusChangedAt *time.Time
+	// PasswordChangedAt es el último cambio de contraseña; los tokens emitidos antes dejan de servir
+	PasswordChangedAt *time.Time
+	CreatedAt         time.Time      `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
 }
@@ -0,0 +1,153 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/config"
+	"users-api/src/dto"
+	"users-api/src/services"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createGroup(t *testing.T, server *apptest.Server, name string) dto.GroupDTO {
+	t.Helper()
+	resp := server.Do(t, http.MethodPost, "/v1/groups/", dto.CreateGroupDTO{Name: name + "-" + uuid.NewString()[:8]})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+
+	var group dto.GroupDTO
+	resp.Decode(t, &group)
+	return group
+}
+
+func TestGroups(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMemory, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			server := apptest.NewServer(t, func(cfg *config.Config) {
+				cfg.DBDriver = driver
+				cfg.SQLitePath = ":memory:"
+			})
+
+			t.Run("CRUD de grupos", func(t *testing.T) {
+				group := createGroup(t, server, "cohorte")
+
+				resp := server.Do(t, http.MethodPost, "/v1/groups/", dto.CreateGroupDTO{Name: group.Name})
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+				assert.Equal(t, "GROUP_EXISTS", errorCode(t, resp))
+
+				description := "Cohorte de marzo"
+				resp = server.Do(t, http.MethodPut, "/v1/groups/"+group.ID, dto.UpdateGroupDTO{Description: &description})
+				require.Equal(t, http.StatusOK, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var updated dto.GroupDTO
+				resp.Decode(t, &updated)
+				assert.Equal(t, group.Name, updated.Name)
+				assert.Equal(t, description, updated.Description)
+
+				assert.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/groups/"+group.ID, nil).StatusCode)
+				assert.Equal(t, http.StatusNoContent, server.Do(t, http.MethodDelete, "/v1/groups/"+group.ID, nil).StatusCode)
+				resp = server.Do(t, http.MethodGet, "/v1/groups/"+group.ID, nil)
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, "GROUP_NOT_FOUND", errorCode(t, resp))
+			})
+
+			t.Run("miembros en bloque y paginados", func(t *testing.T) {
+				group := createGroup(t, server, "cohorte")
+				var ids []string
+				for i := 0; i < 3; i++ {
+					ids = append(ids, createUser(t, server, fmt.Sprintf("miembro%d", i)).ID)
+				}
+
+				// Un usuario inexistente hace fallar toda la operación
+				resp := server.Do(t, http.MethodPost, "/v1/groups/"+group.ID+"/members",
+					dto.GroupMembersDTO{UserIDs: append([]string{uuid.NewString()}, ids...)})
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "UNKNOWN_USERS", errorCode(t, resp))
+
+				var result dto.GroupMembersResultDTO
+				resp = server.Do(t, http.MethodPost, "/v1/groups/"+group.ID+"/members", dto.GroupMembersDTO{UserIDs: ids})
+				require.Equal(t, http.StatusOK, resp.StatusCode, "cuerpo: %s", resp.Body)
+				resp.Decode(t, &result)
+				assert.Equal(t, int64(3), result.Added)
+
+				var members dto.UserPageDTO
+				server.Do(t, http.MethodGet, "/v1/groups/"+group.ID+"/members?limit=2", nil).Decode(t, &members)
+				assert.Equal(t, int64(3), members.Total)
+				assert.Equal(t, 2, members.Limit)
+				require.Len(t, members.Users, 2)
+				server.Do(t, http.MethodGet, "/v1/groups/"+group.ID+"/members?limit=2&offset=2", nil).Decode(t, &members)
+				require.Len(t, members.Users, 1)
+
+				server.Do(t, http.MethodDelete, "/v1/groups/"+group.ID+"/members", dto.GroupMembersDTO{UserIDs: ids[:2]}).Decode(t, &result)
+				assert.Equal(t, int64(2), result.Removed)
+
+				var groups dto.GroupPageDTO
+				server.Do(t, http.MethodGet, "/v1/users/"+ids[2]+"/groups", nil).Decode(t, &groups)
+				assert.Equal(t, int64(1), groups.Total)
+				require.Len(t, groups.Groups, 1)
+				assert.Equal(t, group.ID, groups.Groups[0].ID)
+			})
+
+			t.Run("los usuarios eliminados dejan de ser miembros", func(t *testing.T) {
+				group := createGroup(t, server, "cohorte")
+				var ids []string
+				for i := 0; i < 3; i++ {
+					ids = append(ids, createUser(t, server, fmt.Sprintf("eliminado%d", i)).ID)
+				}
+				resp := server.Do(t, http.MethodPost, "/v1/groups/"+group.ID+"/members", dto.GroupMembersDTO{UserIDs: ids})
+				require.Equal(t, http.StatusOK, resp.StatusCode, "cuerpo: %s", resp.Body)
+				require.Equal(t, http.StatusNoContent, server.Do(t, http.MethodDelete, "/v1/users/"+ids[0], nil).StatusCode)
+
+				// El total y las páginas cuentan solo a los usuarios que siguen existiendo
+				var members dto.UserPageDTO
+				server.Do(t, http.MethodGet, "/v1/groups/"+group.ID+"/members?limit=2", nil).Decode(t, &members)
+				assert.Equal(t, int64(2), members.Total)
+				require.Len(t, members.Users, 2)
+				assert.ElementsMatch(t, ids[1:], []string{members.Users[0].ID, members.Users[1].ID})
+				server.Do(t, http.MethodGet, "/v1/groups/"+group.ID+"/members?limit=2&offset=2", nil).Decode(t, &members)
+				assert.Empty(t, members.Users)
+			})
+
+			t.Run("valida la paginación y el cuerpo", func(t *testing.T) {
+				group := createGroup(t, server, "cohorte")
+				assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodGet, "/v1/groups/?limit=500", nil).StatusCode)
+				assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodGet, "/v1/groups/?offset=-1", nil).StatusCode)
+				assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodPost, "/v1/groups/"+group.ID+"/members",
+					dto.GroupMembersDTO{UserIDs: []string{}}).StatusCode)
+			})
+
+			t.Run("el token incluye los grupos y cada usuario ve los suyos", func(t *testing.T) {
+				group := createGroup(t, server, "cohorte")
+				ana := createUser(t, server, "ana")
+				bruno := createUser(t, server, "bruno")
+				server.Do(t, http.MethodPost, "/v1/groups/"+group.ID+"/members", dto.GroupMembersDTO{UserIDs: []string{ana.ID}})
+
+				token := bearer(t, server, ana.Email)
+				claims := &services.TokenClaims{}
+				_, _, err := jwt.NewParser().ParseUnverified(strings.TrimPrefix(token, "Bearer "), claims)
+				require.NoError(t, err)
+				assert.Equal(t, []string{group.ID}, claims.Groups)
+
+				assert.Equal(t, http.StatusOK, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+ana.ID+"/groups", nil).StatusCode)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+bruno.ID+"/groups", nil).StatusCode)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, token, http.MethodGet, "/v1/groups/", nil).StatusCode)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, token, http.MethodPost, "/v1/groups/", dto.CreateGroupDTO{Name: "propio"}).StatusCode)
+			})
+
+			t.Run("los grupos de otra organización no se ven", func(t *testing.T) {
+				group := createGroup(t, server, "cohorte")
+				acmeKey := createOrganization(t, server, "acme-"+uuid.NewString()[:8])
+
+				assert.Equal(t, http.StatusNotFound, server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/groups/"+group.ID, nil).StatusCode)
+				var groups dto.GroupPageDTO
+				server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/groups/", nil).Decode(t, &groups)
+				assert.Zero(t, groups.Total)
+			})
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package router_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	"users-api/src/apptest"
+	"users-api/src/auth"
+	"users-api/src/config"
+	"users-api/src/dto"
+	"users-api/src/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inbox es el webhook de invitaciones del servidor de pruebas, guarda el último token de cada email
+type inbox struct {
+	mu     sync.Mutex
+	tokens map[string]string
+	fail   bool
+}
+
+func newInbox(t *testing.T) (*inbox, string) {
+	box := &inbox{tokens: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		box.mu.Lock()
+		defer box.mu.Unlock()
+		if box.fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var payload services.InvitationWebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		box.tokens[payload.Invitation.Email] = payload.Token
+	}))
+	t.Cleanup(server.Close)
+	return box, server.URL
+}
+
+func (b *inbox) token(t *testing.T, email string) string {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	token, ok := b.tokens[email]
+	require.True(t, ok, "no llegó la invitación para %s", email)
+	return token
+}
+
+func newEmail(name string) string {
+	return name + "-" + uuid.NewString()[:8] + "@example.com"
+}
+
+func acceptBody(token string) dto.AcceptInvitationDTO {
+	return dto.AcceptInvitationDTO{
+		Token:     token,
+		Name:      "Invitada",
+		Lastname:  "Test",
+		Birthdate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		Password:  "secreto123",
+	}
+}
+
+func TestInvitations(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMemory, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			box, webhookURL := newInbox(t)
+			server := apptest.NewServer(t, func(cfg *config.Config) {
+				cfg.DBDriver = driver
+				cfg.SQLitePath = ":memory:"
+				cfg.InvitationNotifier = config.NotifierWebhook
+				cfg.InvitationWebhook = config.Secret(webhookURL)
+			})
+			admin := bearer(t, server, createAdmin(t, server).Email)
+
+			t.Run("un admin invita con un rol y el invitado crea su cuenta", func(t *testing.T) {
+				email := newEmail("instructora")
+				resp := server.DoWithKey(t, admin, http.MethodPost, "/v1/users/invitations",
+					dto.CreateInvitationDTO{Email: email, Role: auth.RoleAdmin})
+				require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var invitation dto.InvitationDTO
+				resp.Decode(t, &invitation)
+				assert.Equal(t, auth.RoleAdmin, invitation.Role)
+				assert.NotEmpty(t, invitation.InvitedBy)
+				assert.NotContains(t, string(resp.Body), "token")
+
+				var pending dto.InvitationPageDTO
+				server.Do(t, http.MethodGet, "/v1/users/invitations", nil).Decode(t, &pending)
+				assert.Contains(t, pending.Invitations, invitation)
+
+				token := box.token(t, email)
+				resp = server.Do(t, http.MethodPost, "/v1/users/invitations/accept", acceptBody(token))
+				require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var user dto.UserResponseDTO
+				resp.Decode(t, &user)
+				assert.Equal(t, email, user.Email)
+				assert.Equal(t, auth.RoleAdmin, user.Role)
+				bearer(t, server, email)
+
+				// El token sirve una sola vez y la invitación deja de estar pendiente
+				resp = server.Do(t, http.MethodPost, "/v1/users/invitations/accept", acceptBody(token))
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "INVALID_INVITATION", errorCode(t, resp))
+				server.Do(t, http.MethodGet, "/v1/users/invitations", nil).Decode(t, &pending)
+				assert.NotContains(t, pending.Invitations, invitation)
+			})
+
+			t.Run("la API key solo invita con el rol por defecto", func(t *testing.T) {
+				resp := server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: newEmail("a"), Role: auth.RoleAdmin})
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+				resp = server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: newEmail("b")})
+				require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var invitation dto.InvitationDTO
+				resp.Decode(t, &invitation)
+				assert.Equal(t, auth.DefaultRole, invitation.Role)
+				assert.Equal(t, auth.KindService, invitation.InvitedBy)
+
+				user := bearer(t, server, createUser(t, server, "usuario").Email)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, user, http.MethodPost, "/v1/users/invitations",
+					dto.CreateInvitationDTO{Email: newEmail("c")}).StatusCode)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, user, http.MethodGet, "/v1/users/invitations", nil).StatusCode)
+			})
+
+			t.Run("no se invita dos veces ni a un usuario existente", func(t *testing.T) {
+				email := newEmail("doble")
+				require.Equal(t, http.StatusCreated, server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email}).StatusCode)
+				resp := server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email})
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+				assert.Equal(t, "INVITATION_EXISTS", errorCode(t, resp))
+
+				existing := createUser(t, server, "existente")
+				resp = server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: existing.Email})
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+				assert.Equal(t, "USER_EXISTS", errorCode(t, resp))
+			})
+
+			t.Run("valida el vencimiento", func(t *testing.T) {
+				for _, expiresAt := range []time.Time{time.Now().Add(-time.Minute), time.Now().Add(31 * 24 * time.Hour)} {
+					resp := server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: newEmail("vence"), ExpiresAt: &expiresAt})
+					assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+					assert.Equal(t, "INVALID_EXPIRY", errorCode(t, resp))
+				}
+
+				expiresAt := time.Now().Add(time.Hour)
+				resp := server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: newEmail("vence"), ExpiresAt: &expiresAt})
+				require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var invitation dto.InvitationDTO
+				resp.Decode(t, &invitation)
+				assert.WithinDuration(t, expiresAt, invitation.ExpiresAt, time.Second)
+			})
+
+			t.Run("una invitación revocada no se puede aceptar", func(t *testing.T) {
+				email := newEmail("revocada")
+				var invitation dto.InvitationDTO
+				server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email}).Decode(t, &invitation)
+
+				assert.Equal(t, http.StatusNoContent, server.Do(t, http.MethodDelete, "/v1/users/invitations/"+invitation.ID, nil).StatusCode)
+				resp := server.Do(t, http.MethodDelete, "/v1/users/invitations/"+invitation.ID, nil)
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, "INVITATION_NOT_FOUND", errorCode(t, resp))
+
+				resp = server.Do(t, http.MethodPost, "/v1/users/invitations/accept", acceptBody(box.token(t, email)))
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "INVALID_INVITATION", errorCode(t, resp))
+			})
+
+			t.Run("el token no sirve en otra organización ni como token de acceso", func(t *testing.T) {
+				email := newEmail("ajena")
+				require.Equal(t, http.StatusCreated, server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email}).StatusCode)
+				token := box.token(t, email)
+
+				acmeKey := createOrganization(t, server, "acme-"+uuid.NewString()[:8])
+				resp := server.DoWithKey(t, acmeKey, http.MethodPost, "/v1/users/invitations/accept", acceptBody(token))
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "INVALID_INVITATION", errorCode(t, resp))
+
+				assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, "Bearer "+token, http.MethodGet, "/v1/users/", nil).StatusCode)
+				assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodPost, "/v1/users/invitations/accept", acceptBody("no-es-un-token")).StatusCode)
+			})
+
+			t.Run("si el notificador falla la invitación se revoca", func(t *testing.T) {
+				email := newEmail("sin-enviar")
+				box.mu.Lock()
+				box.fail = true
+				box.mu.Unlock()
+				resp := server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email})
+				assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+				assert.Equal(t, "NOTIFICATION_FAILED", errorCode(t, resp))
+
+				box.mu.Lock()
+				box.fail = false
+				box.mu.Unlock()
+				assert.Equal(t, http.StatusCreated, server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email}).StatusCode)
+			})
+		})
+	}
+}
+
+// logBuffer guarda la salida de los logs del servidor de pruebas, que escriben varios goroutines
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// field devuelve el valor de key en la línea de log con id, o vacío si no la hay. En modo development
+// los campos van en JSON al final de la línea
+func (b *logBuffer) field(id, key string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	scanner := bufio.NewScanner(bytes.NewReader(b.buf.Bytes()))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		start := bytes.IndexByte(line, '{')
+		if start < 0 {
+			continue
+		}
+		var entry map[string]interface{}
+		if json.Unmarshal(line[start:], &entry) == nil && entry["id"] == id {
+			value, _ := entry[key].(string)
+			return value
+		}
+	}
+	return ""
+}
+
+func TestInvitationLogNotifier(t *testing.T) {
+	logs := &logBuffer{}
+	server := apptest.NewServerWithLogs(t, logs, func(cfg *config.Config) {
+		cfg.InvitationNotifier = config.NotifierLog
+		cfg.LogLevel = "info"
+	})
+
+	email := newEmail("desde-el-log")
+	resp := server.Do(t, http.MethodPost, "/v1/users/invitations", dto.CreateInvitationDTO{Email: email})
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+	var invitation dto.InvitationDTO
+	resp.Decode(t, &invitation)
+
+	// El token se lee del log tal como lo haría quien prueba la API en local
+	token := logs.field(invitation.ID, "token")
+	require.NotEmpty(t, token)
+	assert.NotEqual(t, "[REDACTED]", token)
+
+	resp = server.Do(t, http.MethodPost, "/v1/users/invitations/accept", acceptBody(token))
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+	var user dto.UserResponseDTO
+	resp.Decode(t, &user)
+	assert.Equal(t, email, user.Email)
+}
@@ -0,0 +1,74 @@
+package router_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/docs"
+	"users-api/src/router"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ginParam = regexp.MustCompile(`[:*](\w+)`)
+
+// openAPIPath traduce una ruta de gin (/users/:id) al formato de OpenAPI (/users/{id})
+func openAPIPath(path string) string {
+	return ginParam.ReplaceAllString(path, "{$1}")
+}
+
+func specOperations(t *testing.T) map[string]map[string]json.RawMessage {
+	t.Helper()
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(docs.Spec(), &spec))
+	return spec.Paths
+}
+
+func TestOpenAPICoversEveryRoute(t *testing.T) {
+	server := apptest.NewServer(t)
+	paths := specOperations(t)
+
+	registered := map[string]bool{}
+	for _, route := range server.App.GetRouter().Routes() {
+		registered[strings.ToLower(route.Method)+" "+openAPIPath(route.Path)] = true
+	}
+
+	for key := range registered {
+		method, path, _ := strings.Cut(key, " ")
+		if _, ok := paths[path][method]; ok {
+			continue
+		}
+		// Los alias deprecados sin versión se documentan a través de su ruta versionada
+		successor := "/" + router.LatestStableVersion + path
+		_, ok := paths[successor][method]
+		assert.True(t, ok && registered[method+" "+successor], "la ruta %s %s no está en src/docs/openapi.json", strings.ToUpper(method), path)
+	}
+
+	for path, item := range paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			assert.True(t, registered[method+" "+path], "openapi.json describe %s %s pero la ruta no existe", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestDocsRoutes(t *testing.T) {
+	server := apptest.NewServer(t)
+
+	resp := server.DoWithKey(t, "", http.MethodGet, "/openapi.json", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+	assert.JSONEq(t, string(docs.Spec()), string(resp.Body))
+
+	resp = server.DoWithKey(t, "", http.MethodGet, "/docs", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+}
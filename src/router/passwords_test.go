@@ -0,0 +1,304 @@
+package router_test
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/auth"
+	"users-api/src/config"
+	"users-api/src/dto"
+	"users-api/src/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storedHash lee el hash guardado de la contraseña, que la API nunca devuelve
+func storedHash(t *testing.T, server *apptest.Server, id string) string {
+	t.Helper()
+	ctx := auth.WithPrincipal(context.Background(), auth.System(models.DefaultOrganizationID))
+	user, err := server.App.GetUserRepo().ReadOne(ctx, id)
+	require.NoError(t, err)
+	return user.Password
+}
+
+func login(t *testing.T, server *apptest.Server, email, password string) int {
+	t.Helper()
+	return server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: email, Password: password}).StatusCode
+}
+
+func TestPasswordHashing(t *testing.T) {
+	t.Run("los hashes se actualizan al algoritmo actual en el login", func(t *testing.T) {
+		// Dos servidores sobre la misma base, como durante un cambio de PASSWORD_HASHER
+		path := filepath.Join(t.TempDir(), "users.db")
+		withHasher := func(hasher string) func(*config.Config) {
+			return func(cfg *config.Config) {
+				cfg.DBDriver = config.DBDriverSQLite
+				cfg.SQLitePath = path
+				cfg.PasswordHasher = hasher
+				cfg.BcryptCost = 4
+				cfg.PasswordMaxLength = 72
+			}
+		}
+		legacy := apptest.NewServer(t, withHasher(config.HasherBcrypt))
+		current := apptest.NewServer(t, withHasher(config.HasherArgon2id))
+
+		user := createUser(t, legacy, "bcrypt")
+		assert.True(t, strings.HasPrefix(storedHash(t, legacy, user.ID), "$2a$04$"))
+
+		// Con la contraseña incorrecta no se toca el hash
+		assert.Equal(t, http.StatusUnauthorized, login(t, current, user.Email, "incorrecta"))
+		assert.True(t, strings.HasPrefix(storedHash(t, current, user.ID), "$2a$04$"))
+
+		assert.Equal(t, http.StatusOK, login(t, current, user.Email, "secreto123"))
+		upgraded := storedHash(t, current, user.ID)
+		assert.True(t, strings.HasPrefix(upgraded, "$argon2id$v=19$m=19456,t=2,p=1$"), upgraded)
+		assert.Equal(t, http.StatusOK, login(t, current, user.Email, "secreto123"))
+		assert.Equal(t, upgraded, storedHash(t, current, user.ID))
+
+		// El servidor con bcrypt sigue aceptando el hash argon2id, y lo vuelve a su algoritmo
+		assert.Equal(t, http.StatusOK, login(t, legacy, user.Email, "secreto123"))
+		assert.True(t, strings.HasPrefix(storedHash(t, legacy, user.ID), "$2a$04$"))
+	})
+
+	t.Run("bcrypt rechaza las contraseñas de más de 72 bytes", func(t *testing.T) {
+		server := apptest.NewServer(t, func(cfg *config.Config) {
+			cfg.PasswordHasher = config.HasherBcrypt
+			cfg.BcryptCost = 4
+			cfg.PasswordMaxLength = 72
+		})
+		body := newUserBody("larga")
+		body["password"] = strings.Repeat("a", 73)
+		resp := server.Do(t, http.MethodPost, "/v1/users/", body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "PASSWORD_TOO_LONG", errorCode(t, resp))
+
+		// 40 caracteres entran en la política pero ocupan 80 bytes
+		body["password"] = strings.Repeat("ñ", 40)
+		resp = server.Do(t, http.MethodPost, "/v1/users/", body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "PASSWORD_EXCEEDS_BCRYPT_LIMIT", errorCode(t, resp))
+	})
+
+	t.Run("bcrypt no admite un máximo de más de 72", func(t *testing.T) {
+		cfg := apptest.Config()
+		cfg.PasswordHasher = config.HasherBcrypt
+		assert.ErrorContains(t, cfg.Validate(), "PASSWORD_MAX_LENGTH")
+		cfg.PasswordMaxLength = 72
+		assert.NoError(t, cfg.Validate())
+	})
+
+	t.Run("argon2id usa la contraseña completa", func(t *testing.T) {
+		server := apptest.NewServer(t)
+		password := strings.Repeat("a", 72) + "-resto-de-la-contraseña"
+		body := newUserBody("larga")
+		body["password"] = password
+		resp := server.Do(t, http.MethodPost, "/v1/users/", body)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+		var user dto.UserResponseDTO
+		resp.Decode(t, &user)
+
+		assert.Equal(t, http.StatusOK, login(t, server, user.Email, password))
+		assert.Equal(t, http.StatusUnauthorized, login(t, server, user.Email, password[:72]))
+	})
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	createWith := func(t *testing.T, server *apptest.Server, password string, fields map[string]interface{}) *apptest.Response {
+		t.Helper()
+		body := newUserBody("politica")
+		body["password"] = password
+		for key, value := range fields {
+			body[key] = value
+		}
+		return server.Do(t, http.MethodPost, "/v1/users/", body)
+	}
+
+	t.Run("cada regla responde con su código", func(t *testing.T) {
+		server := apptest.NewServer(t)
+		for password, code := range map[string]string{
+			"corta1":                 "PASSWORD_TOO_SHORT",
+			strings.Repeat("a1", 65): "PASSWORD_TOO_LONG",
+			"password123":            "PASSWORD_BREACHED",
+			"contraseña123":          "PASSWORD_BREACHED",
+			"ana.perez@example.com":  "PASSWORD_MATCHES_USER_DATA",
+			"Ana.Perez":              "PASSWORD_MATCHES_USER_DATA",
+			"Anabela Perez Gonzalez": "PASSWORD_MATCHES_USER_DATA",
+			"anabelaperezgonzalez":   "PASSWORD_MATCHES_USER_DATA",
+		} {
+			resp := createWith(t, server, password, map[string]interface{}{
+				"name": "Anabela", "lastname": "Perez Gonzalez", "email": "ana.perez@example.com",
+			})
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, password)
+			assert.Equal(t, code, errorCode(t, resp), password)
+		}
+		assert.Equal(t, http.StatusCreated, createWith(t, server, "anabela perez", nil).StatusCode)
+	})
+
+	t.Run("las clases de caracteres son configurables", func(t *testing.T) {
+		server := apptest.NewServer(t, func(cfg *config.Config) {
+			cfg.PasswordClasses = []string{config.PasswordClassUpper, config.PasswordClassDigit, config.PasswordClassSymbol}
+		})
+		for password, code := range map[string]string{
+			"secreto123":  "PASSWORD_MISSING_UPPERCASE",
+			"Secretisimo": "PASSWORD_MISSING_DIGIT",
+			"Secreto123":  "PASSWORD_MISSING_SYMBOL",
+		} {
+			resp := createWith(t, server, password, nil)
+			assert.Equal(t, code, errorCode(t, resp), password)
+		}
+		assert.Equal(t, http.StatusCreated, createWith(t, server, "Secreto 123", nil).StatusCode)
+	})
+
+	t.Run("se aplica al actualizar y al resetear", func(t *testing.T) {
+		server := apptest.NewServer(t)
+		user := createUser(t, server, "existente")
+
+		resp := server.Do(t, http.MethodPut, "/v1/users/"+user.ID+"/password", dto.ChangePasswordDTO{CurrentPassword: "secreto123", NewPassword: "qwerty123"})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "PASSWORD_BREACHED", errorCode(t, resp))
+		resp = server.Do(t, http.MethodPut, "/v1/users/"+user.ID+"/password", dto.ChangePasswordDTO{CurrentPassword: "secreto123", NewPassword: user.Email})
+		assert.Equal(t, "PASSWORD_MATCHES_USER_DATA", errorCode(t, resp))
+
+		ctx := auth.WithPrincipal(context.Background(), auth.System(models.DefaultOrganizationID))
+		err := server.App.GetUserService().ResetPassword(ctx, user.ID, "123456789")
+		assert.ErrorContains(t, err, "PASSWORD_BREACHED")
+		require.NoError(t, server.App.GetUserService().ResetPassword(ctx, user.ID, "otra-contraseña-segura"))
+		assert.Equal(t, http.StatusOK, login(t, server, user.Email, "otra-contraseña-segura"))
+	})
+
+	t.Run("la lista de filtraciones se puede reemplazar o deshabilitar", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "breached.txt")
+		sum := sha1.Sum([]byte("secreto123"))
+		require.NoError(t, os.WriteFile(path, []byte(strings.ToUpper(hex.EncodeToString(sum[:]))+":42\n"), 0o600))
+
+		server := apptest.NewServer(t, func(cfg *config.Config) {
+			cfg.PasswordBreachFile = path
+		})
+		assert.Equal(t, "PASSWORD_BREACHED", errorCode(t, createWith(t, server, "secreto123", nil)))
+		assert.Equal(t, http.StatusCreated, createWith(t, server, "password123", nil).StatusCode)
+
+		server = apptest.NewServer(t, func(cfg *config.Config) {
+			cfg.PasswordBreached = false
+		})
+		assert.Equal(t, http.StatusCreated, createWith(t, server, "password123", nil).StatusCode)
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMemory, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			server := apptest.NewServer(t, func(cfg *config.Config) {
+				cfg.DBDriver = driver
+				cfg.SQLitePath = ":memory:"
+				cfg.PasswordHistory = 3
+			})
+			changePassword := func(t *testing.T, key, id string, body dto.ChangePasswordDTO) *apptest.Response {
+				t.Helper()
+				return server.DoWithKey(t, key, http.MethodPut, "/v1/users/"+id+"/password", body)
+			}
+
+			t.Run("la actualización genérica no cambia la contraseña", func(t *testing.T) {
+				user := createUser(t, server, "generica")
+				resp := server.Do(t, http.MethodPut, "/v1/users/"+user.ID, map[string]interface{}{"name": "Otro", "password": "nueva-contraseña"})
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "PASSWORD_CHANGE_NOT_ALLOWED", errorCode(t, resp))
+				assert.Equal(t, http.StatusOK, login(t, server, user.Email, "secreto123"))
+				assert.True(t, strings.HasPrefix(storedHash(t, server, user.ID), "$argon2id$"))
+			})
+
+			t.Run("el usuario cambia su contraseña con la actual y se revocan sus tokens", func(t *testing.T) {
+				user := createUser(t, server, "propia")
+				token := bearer(t, server, user.Email)
+				other := bearer(t, server, user.Email)
+
+				resp := changePassword(t, token, user.ID, dto.ChangePasswordDTO{NewPassword: "nueva-contraseña"})
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "CURRENT_PASSWORD_REQUIRED", errorCode(t, resp))
+				resp = changePassword(t, token, user.ID, dto.ChangePasswordDTO{CurrentPassword: "incorrecta", NewPassword: "nueva-contraseña"})
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				assert.Equal(t, "INVALID_CURRENT_PASSWORD", errorCode(t, resp))
+
+				resp = changePassword(t, token, user.ID, dto.ChangePasswordDTO{CurrentPassword: "secreto123", NewPassword: "nueva-contraseña"})
+				require.Equal(t, http.StatusNoContent, resp.StatusCode, "cuerpo: %s", resp.Body)
+
+				for _, revoked := range []string{token, other} {
+					assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, revoked, http.MethodGet, "/v1/users/"+user.ID, nil).StatusCode)
+				}
+				assert.Equal(t, http.StatusUnauthorized, login(t, server, user.Email, "secreto123"))
+				assert.Equal(t, http.StatusOK, login(t, server, user.Email, "nueva-contraseña"))
+
+				var fresh dto.LoginResponseDTO
+				server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: user.Email, Password: "nueva-contraseña"}).Decode(t, &fresh)
+				resp = server.DoWithKey(t, "Bearer "+fresh.AccessToken, http.MethodGet, "/v1/users/"+user.ID, nil)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var updated dto.UserResponseDTO
+				resp.Decode(t, &updated)
+				assert.NotNil(t, updated.PasswordChangedAt)
+			})
+
+			t.Run("no se reutilizan las últimas contraseñas", func(t *testing.T) {
+				user := createUser(t, server, "historial")
+				passwords := []string{"secreto123", "segunda-clave", "tercera-clave", "cuarta-clave"}
+				for i := 1; i < len(passwords); i++ {
+					current := passwords[i-1]
+					resp := changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{CurrentPassword: current, NewPassword: current})
+					assert.Equal(t, "PASSWORD_REUSED", errorCode(t, resp))
+					resp = changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{CurrentPassword: current, NewPassword: passwords[i]})
+					require.Equal(t, http.StatusNoContent, resp.StatusCode, "cuerpo: %s", resp.Body)
+				}
+
+				// Con PASSWORD_HISTORY=3 no se pueden usar la actual ni las dos anteriores
+				current := passwords[len(passwords)-1]
+				for _, password := range passwords[1:] {
+					resp := changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{CurrentPassword: current, NewPassword: password})
+					assert.Equal(t, "PASSWORD_REUSED", errorCode(t, resp), password)
+				}
+				resp := changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{CurrentPassword: current, NewPassword: passwords[0]})
+				assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+				assert.Equal(t, http.StatusOK, login(t, server, user.Email, passwords[0]))
+			})
+
+			t.Run("la API key necesita la contraseña actual", func(t *testing.T) {
+				user := createUser(t, server, "servicio")
+
+				resp := changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{NewPassword: "nueva-contraseña"})
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "CURRENT_PASSWORD_REQUIRED", errorCode(t, resp))
+				resp = changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{CurrentPassword: "incorrecta", NewPassword: "nueva-contraseña"})
+				assert.Equal(t, "INVALID_CURRENT_PASSWORD", errorCode(t, resp))
+				assert.Equal(t, http.StatusOK, login(t, server, user.Email, "secreto123"))
+
+				resp = changePassword(t, apptest.APIKey, user.ID, dto.ChangePasswordDTO{CurrentPassword: "secreto123", NewPassword: "nueva-contraseña"})
+				require.Equal(t, http.StatusNoContent, resp.StatusCode, "cuerpo: %s", resp.Body)
+				assert.Equal(t, http.StatusOK, login(t, server, user.Email, "nueva-contraseña"))
+			})
+
+			t.Run("un admin cambia la contraseña de otro sin la actual", func(t *testing.T) {
+				admin := bearer(t, server, createAdmin(t, server).Email)
+				user := createUser(t, server, "olvidadiza")
+				intruder := bearer(t, server, createUser(t, server, "intrusa").Email)
+
+				assert.Equal(t, http.StatusForbidden, changePassword(t, intruder, user.ID,
+					dto.ChangePasswordDTO{CurrentPassword: "secreto123", NewPassword: "nueva-contraseña"}).StatusCode)
+
+				// Si el admin indica la contraseña actual, se verifica
+				resp := changePassword(t, admin, user.ID, dto.ChangePasswordDTO{CurrentPassword: "incorrecta", NewPassword: "nueva-contraseña"})
+				assert.Equal(t, "INVALID_CURRENT_PASSWORD", errorCode(t, resp))
+
+				require.Equal(t, http.StatusNoContent, changePassword(t, admin, user.ID, dto.ChangePasswordDTO{NewPassword: "nueva-contraseña"}).StatusCode)
+				assert.Equal(t, http.StatusOK, login(t, server, user.Email, "nueva-contraseña"))
+				assert.Equal(t, http.StatusOK, server.DoWithKey(t, admin, http.MethodGet, "/v1/users/"+user.ID, nil).StatusCode)
+
+				assert.Equal(t, http.StatusNotFound, changePassword(t, admin, "no-existe", dto.ChangePasswordDTO{NewPassword: "nueva-contraseña"}).StatusCode)
+				assert.Equal(t, http.StatusBadRequest, changePassword(t, admin, user.ID, dto.ChangePasswordDTO{}).StatusCode)
+			})
+		})
+	}
+}
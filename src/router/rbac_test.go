@@ -0,0 +1,180 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"users-api/src/apptest"
+	"users-api/src/auth"
+	"users-api/src/config"
+	"users-api/src/dto"
+	"users-api/src/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createAdmin crea un administrador como lo haría el CLI, que es la única forma de obtener el primero
+func createAdmin(t *testing.T, server *apptest.Server) *dto.UserResponseDTO {
+	t.Helper()
+	ctx := auth.WithPrincipal(context.Background(), auth.System(models.DefaultOrganizationID))
+	admin, err := server.App.GetUserService().CreateUser(ctx, &dto.CreateUserDTO{
+		Name:      "admin",
+		Lastname:  "Test",
+		Birthdate: time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC),
+		Role:      auth.RoleAdmin,
+		Email:     "admin-" + uuid.NewString()[:8] + "@example.com",
+		Password:  "secreto123",
+	})
+	require.NoError(t, err)
+	return admin
+}
+
+// bearer inicia sesión y devuelve el encabezado Authorization con el token de acceso
+func bearer(t *testing.T, server *apptest.Server, email string) string {
+	t.Helper()
+	resp := server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: email, Password: "secreto123"})
+	require.Equal(t, http.StatusOK, resp.StatusCode, "cuerpo: %s", resp.Body)
+
+	var login dto.LoginResponseDTO
+	resp.Decode(t, &login)
+	require.NotEmpty(t, login.AccessToken)
+	require.NotNil(t, login.ExpiresAt)
+	return "Bearer " + login.AccessToken
+}
+
+func errorCode(t *testing.T, resp *apptest.Response) string {
+	t.Helper()
+	var body struct {
+		Code string `json:"code"`
+	}
+	resp.Decode(t, &body)
+	return body.Code
+}
+
+func TestRoleAssignment(t *testing.T) {
+	server := apptest.NewServer(t)
+
+	t.Run("la API key no puede crear administradores", func(t *testing.T) {
+		body := newUserBody("ana")
+		body["role"] = auth.RoleAdmin
+		resp := server.Do(t, http.MethodPost, "/v1/users/", body)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "FORBIDDEN", errorCode(t, resp))
+
+		body["role"] = auth.RoleUser
+		assert.Equal(t, http.StatusCreated, server.Do(t, http.MethodPost, "/v1/users/", body).StatusCode)
+	})
+
+	t.Run("la API key no puede cambiar roles", func(t *testing.T) {
+		ana := createUser(t, server, "ana")
+		resp := server.Do(t, http.MethodPut, "/v1/users/"+ana.ID, map[string]string{"role": auth.RoleAdmin})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		// Enviar el mismo rol no es un cambio
+		resp = server.Do(t, http.MethodPut, "/v1/users/"+ana.ID, map[string]string{"role": auth.RoleUser, "name": "Ana"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	admin := bearer(t, server, createAdmin(t, server).Email)
+
+	t.Run("un administrador asigna roles existentes", func(t *testing.T) {
+		body := newUserBody("beto")
+		body["role"] = auth.RoleAdmin
+		resp := server.DoWithKey(t, admin, http.MethodPost, "/v1/users/", body)
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+
+		var beto dto.UserResponseDTO
+		resp.Decode(t, &beto)
+		assert.Equal(t, auth.RoleAdmin, beto.Role)
+
+		resp = server.DoWithKey(t, admin, http.MethodPut, "/v1/users/"+beto.ID, map[string]string{"role": auth.RoleUser})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp.Decode(t, &beto)
+		assert.Equal(t, auth.RoleUser, beto.Role)
+	})
+
+	t.Run("un rol inexistente responde 400", func(t *testing.T) {
+		body := newUserBody("carla")
+		body["role"] = "superadmin"
+		resp := server.DoWithKey(t, admin, http.MethodPost, "/v1/users/", body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Equal(t, "INVALID_ROLE", errorCode(t, resp))
+	})
+}
+
+func TestUserPermissions(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := createUser(t, server, "ana")
+	beto := createUser(t, server, "beto")
+	token := bearer(t, server, ana.Email)
+
+	t.Run("opera sobre su propia cuenta", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+		assert.Equal(t, http.StatusOK, server.DoWithKey(t, token, http.MethodGet, "/v1/users/email/"+ana.Email, nil).StatusCode)
+		resp := server.DoWithKey(t, token, http.MethodPut, "/v1/users/"+ana.ID, map[string]string{"name": "Ana María"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("no accede a otros usuarios", func(t *testing.T) {
+		requests := []struct {
+			method, path string
+			body         interface{}
+		}{
+			{http.MethodGet, "/v1/users/" + beto.ID, nil},
+			{http.MethodGet, "/v1/users/email/" + beto.Email, nil},
+			{http.MethodGet, "/v1/users/", nil},
+			{http.MethodGet, "/v1/users/list", map[string][]string{"ids": {ana.ID}}},
+			{http.MethodPost, "/v1/users/", newUserBody("carla")},
+			{http.MethodPut, "/v1/users/" + beto.ID, map[string]string{"name": "x"}},
+			{http.MethodDelete, "/v1/users/" + beto.ID, nil},
+			{http.MethodGet, "/v1/admin/cache/stats", nil},
+		}
+		for _, r := range requests {
+			resp := server.DoWithKey(t, token, r.method, r.path, r.body)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode, "%s %s: %s", r.method, r.path, resp.Body)
+		}
+	})
+
+	t.Run("no puede darse otro rol", func(t *testing.T) {
+		resp := server.DoWithKey(t, token, http.MethodPut, "/v1/users/"+ana.ID, map[string]string{"role": auth.RoleAdmin})
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("un token inválido responde 401", func(t *testing.T) {
+		resp := server.DoWithKey(t, token+"x", http.MethodGet, "/v1/users/"+ana.ID, nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("puede eliminar su cuenta", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, server.DoWithKey(t, token, http.MethodDelete, "/v1/users/"+ana.ID, nil).StatusCode)
+	})
+}
+
+func TestListRoles(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMemory, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			server := apptest.NewServer(t, func(cfg *config.Config) {
+				cfg.DBDriver = driver
+				cfg.SQLitePath = ":memory:"
+			})
+
+			resp := server.Do(t, http.MethodGet, "/v1/roles", nil)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var roles []dto.RoleDTO
+			resp.Decode(t, &roles)
+			require.Len(t, roles, 2)
+			assert.Equal(t, auth.RoleAdmin, roles[0].Name)
+			assert.ElementsMatch(t, auth.DefaultRoles[auth.RoleAdmin], roles[0].Permissions)
+			assert.Equal(t, auth.RoleUser, roles[1].Name)
+			assert.ElementsMatch(t, auth.DefaultRoles[auth.RoleUser], roles[1].Permissions)
+
+			// Los permisos del token salen del rol sembrado en la base
+			admin := bearer(t, server, createAdmin(t, server).Email)
+			assert.Equal(t, http.StatusOK, server.DoWithKey(t, admin, http.MethodGet, "/v1/users/", nil).StatusCode)
+		})
+	}
+}
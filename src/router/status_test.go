@@ -0,0 +1,132 @@
+package router_test
+
+import (
+	"net/http"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/config"
+	"users-api/src/dto"
+	"users-api/src/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func changeStatus(t *testing.T, server *apptest.Server, key, id, status string) *apptest.Response {
+	t.Helper()
+	return server.DoWithKey(t, key, http.MethodPut, "/v1/users/"+id+"/status", dto.UpdateUserStatusDTO{Status: status, Reason: "prueba"})
+}
+
+func TestAccountStatus(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMemory, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			server := apptest.NewServer(t, func(cfg *config.Config) {
+				cfg.DBDriver = driver
+				cfg.SQLitePath = ":memory:"
+			})
+			admin := bearer(t, server, createAdmin(t, server).Email)
+
+			t.Run("una cuenta suspendida no inicia sesión ni usa su token", func(t *testing.T) {
+				user := createUser(t, server, "suspendida")
+				assert.Equal(t, models.UserStatusActive, user.Status)
+				token := bearer(t, server, user.Email)
+
+				resp := server.DoWithKey(t, admin, http.MethodPut, "/v1/users/"+user.ID+"/status",
+					dto.UpdateUserStatusDTO{Status: models.UserStatusSuspended, Reason: "pagos atrasados"})
+				require.Equal(t, http.StatusOK, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var suspended dto.UserResponseDTO
+				resp.Decode(t, &suspended)
+				assert.Equal(t, models.UserStatusSuspended, suspended.Status)
+				assert.Equal(t, "pagos atrasados", suspended.StatusReason)
+				assert.NotNil(t, suspended.StatusChangedAt)
+
+				resp = server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: user.Email, Password: "secreto123"})
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				assert.Equal(t, "ACCOUNT_SUSPENDED", errorCode(t, resp))
+				// Con la contraseña incorrecta no se revela el estado
+				resp = server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: user.Email, Password: "incorrecta"})
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+user.ID, nil).StatusCode)
+
+				require.Equal(t, http.StatusOK, changeStatus(t, server, admin, user.ID, models.UserStatusActive).StatusCode)
+				assert.Equal(t, http.StatusOK, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+user.ID, nil).StatusCode)
+				bearer(t, server, user.Email)
+			})
+
+			t.Run("una cuenta pendiente se activa", func(t *testing.T) {
+				body := newUserBody("pendiente")
+				body["status"] = models.UserStatusPending
+				resp := server.Do(t, http.MethodPost, "/v1/users/", body)
+				require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+				var user dto.UserResponseDTO
+				resp.Decode(t, &user)
+				assert.Equal(t, models.UserStatusPending, user.Status)
+
+				resp = server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: user.Email, Password: "secreto123"})
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+				assert.Equal(t, "ACCOUNT_PENDING", errorCode(t, resp))
+
+				resp = changeStatus(t, server, admin, user.ID, models.UserStatusSuspended)
+				assert.Equal(t, http.StatusConflict, resp.StatusCode)
+				assert.Equal(t, "INVALID_STATUS_TRANSITION", errorCode(t, resp))
+
+				require.Equal(t, http.StatusOK, changeStatus(t, server, admin, user.ID, models.UserStatusActive).StatusCode)
+				bearer(t, server, user.Email)
+
+				body = newUserBody("suspendida")
+				body["status"] = models.UserStatusSuspended
+				assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodPost, "/v1/users/", body).StatusCode)
+			})
+
+			t.Run("deshabilitar es definitivo", func(t *testing.T) {
+				user := createUser(t, server, "deshabilitada")
+				require.Equal(t, http.StatusOK, changeStatus(t, server, admin, user.ID, models.UserStatusDisabled).StatusCode)
+
+				resp := server.Do(t, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: user.Email, Password: "secreto123"})
+				assert.Equal(t, "ACCOUNT_DISABLED", errorCode(t, resp))
+				for _, status := range []string{models.UserStatusActive, models.UserStatusSuspended, models.UserStatusDisabled} {
+					assert.Equal(t, http.StatusConflict, changeStatus(t, server, admin, user.ID, status).StatusCode)
+				}
+			})
+
+			t.Run("valida el cuerpo y los permisos", func(t *testing.T) {
+				user := createUser(t, server, "usuario")
+				token := bearer(t, server, user.Email)
+
+				assert.Equal(t, http.StatusBadRequest, changeStatus(t, server, admin, user.ID, "borrado").StatusCode)
+				assert.Equal(t, http.StatusBadRequest, server.DoWithKey(t, admin, http.MethodPut, "/v1/users/"+user.ID+"/status",
+					dto.UpdateUserStatusDTO{Status: models.UserStatusSuspended}).StatusCode)
+				assert.Equal(t, http.StatusNotFound, changeStatus(t, server, admin, "no-existe", models.UserStatusSuspended).StatusCode)
+
+				assert.Equal(t, http.StatusForbidden, changeStatus(t, server, apptest.APIKey, user.ID, models.UserStatusSuspended).StatusCode)
+				assert.Equal(t, http.StatusForbidden, changeStatus(t, server, token, user.ID, models.UserStatusSuspended).StatusCode)
+			})
+
+			t.Run("el listado filtra por estado", func(t *testing.T) {
+				user := createUser(t, server, "filtrada")
+				require.Equal(t, http.StatusOK, changeStatus(t, server, admin, user.ID, models.UserStatusSuspended).StatusCode)
+
+				var users []dto.UserResponseDTO
+				server.Do(t, http.MethodGet, "/v1/users/?status=suspended", nil).Decode(t, &users)
+				require.NotEmpty(t, users)
+				ids := []string{}
+				for _, listed := range users {
+					assert.Equal(t, models.UserStatusSuspended, listed.Status)
+					ids = append(ids, listed.ID)
+				}
+				assert.Contains(t, ids, user.ID)
+
+				resp := server.Do(t, http.MethodGet, "/v1/users/?status=borrado", nil)
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Equal(t, "INVALID_STATUS", errorCode(t, resp))
+			})
+
+			t.Run("el token de un usuario eliminado deja de servir", func(t *testing.T) {
+				user := createUser(t, server, "eliminada")
+				token := bearer(t, server, user.Email)
+				require.Equal(t, http.StatusNoContent, server.Do(t, http.MethodDelete, "/v1/users/"+user.ID, nil).StatusCode)
+				assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+user.ID, nil).StatusCode)
+			})
+		})
+	}
+}
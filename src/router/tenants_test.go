@@ -0,0 +1,112 @@
+package router_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/auth"
+	"users-api/src/cache"
+	"users-api/src/config"
+	"users-api/src/dto"
+	"users-api/src/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createOrganization crea una organización como lo haría el CLI y devuelve su API key
+func createOrganization(t *testing.T, server *apptest.Server, id string) string {
+	t.Helper()
+	ctx := auth.WithPrincipal(context.Background(), auth.System(models.DefaultOrganizationID))
+	organization, apiKey, err := server.App.GetOrganizationService().CreateOrganization(ctx, id, "")
+	require.NoError(t, err)
+	require.Equal(t, id, organization.ID)
+	return apiKey
+}
+
+func TestTenants(t *testing.T) {
+	for _, driver := range []string{config.DBDriverMemory, config.DBDriverSQLite} {
+		t.Run(driver, func(t *testing.T) {
+			server := apptest.NewServer(t, func(cfg *config.Config) {
+				cfg.DBDriver = driver
+				cfg.SQLitePath = ":memory:"
+			})
+			acmeKey := createOrganization(t, server, "acme")
+
+			body := newUserBody("ana")
+			resp := server.Do(t, http.MethodPost, "/v1/users/", body)
+			require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+			var ana dto.UserResponseDTO
+			resp.Decode(t, &ana)
+			assert.Equal(t, models.DefaultOrganizationID, ana.TenantID)
+
+			// El mismo email se puede registrar en otra organización, no dos veces en la misma
+			resp = server.DoWithKey(t, acmeKey, http.MethodPost, "/v1/users/", body)
+			require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+			var acmeAna dto.UserResponseDTO
+			resp.Decode(t, &acmeAna)
+			assert.Equal(t, "acme", acmeAna.TenantID)
+			assert.NotEqual(t, ana.ID, acmeAna.ID)
+			assert.NotEqual(t, http.StatusCreated, server.DoWithKey(t, acmeKey, http.MethodPost, "/v1/users/", body).StatusCode)
+
+			t.Run("cada organización ve solo sus usuarios", func(t *testing.T) {
+				assert.Equal(t, http.StatusNotFound, server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+				assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodGet, "/v1/users/"+acmeAna.ID, nil).StatusCode)
+
+				resp := server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/users/email/"+ana.Email, nil)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var user dto.UserResponseDTO
+				resp.Decode(t, &user)
+				assert.Equal(t, acmeAna.ID, user.ID)
+
+				var users []dto.UserResponseDTO
+				server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/users/", nil).Decode(t, &users)
+				require.Len(t, users, 1)
+				assert.Equal(t, acmeAna.ID, users[0].ID)
+			})
+
+			t.Run("no se puede modificar ni eliminar un usuario de otra organización", func(t *testing.T) {
+				resp := server.DoWithKey(t, acmeKey, http.MethodPut, "/v1/users/"+ana.ID, map[string]string{"name": "Otra"})
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Equal(t, http.StatusNotFound, server.DoWithKey(t, acmeKey, http.MethodDelete, "/v1/users/"+ana.ID, nil).StatusCode)
+				assert.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+			})
+
+			t.Run("el token lleva la organización del usuario", func(t *testing.T) {
+				resp := server.DoWithKey(t, acmeKey, http.MethodPost, "/v1/users/login", dto.LoginDTO{Email: ana.Email, Password: "secreto123"})
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				var login dto.LoginResponseDTO
+				resp.Decode(t, &login)
+				assert.Equal(t, acmeAna.ID, login.ID)
+
+				token := "Bearer " + login.AccessToken
+				assert.Equal(t, http.StatusOK, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+acmeAna.ID, nil).StatusCode)
+				assert.Equal(t, http.StatusForbidden, server.DoWithKey(t, token, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+			})
+
+			t.Run("la caché se administra por organización", func(t *testing.T) {
+				require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+				require.Equal(t, http.StatusOK, server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/users/"+acmeAna.ID, nil).StatusCode)
+
+				var acmeStats cache.Stats
+				server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/admin/cache/stats", nil).Decode(t, &acmeStats)
+				assert.Equal(t, int64(1), acmeStats.Keys["user_id"])
+				assert.NotContains(t, acmeStats.Keys, "org_key")
+
+				resp := server.DoWithKey(t, acmeKey, http.MethodDelete, "/v1/admin/cache", nil)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				server.DoWithKey(t, acmeKey, http.MethodGet, "/v1/admin/cache/stats", nil).Decode(t, &acmeStats)
+				for keyspace, n := range acmeStats.Keys {
+					assert.Zero(t, n, keyspace)
+				}
+				// La otra organización conserva sus claves
+				assert.Positive(t, cacheStats(t, server).Keys["user_id"])
+			})
+
+			t.Run("una API key desconocida se rechaza", func(t *testing.T) {
+				assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, "otra-key", http.MethodGet, "/v1/users/", nil).StatusCode)
+			})
+		})
+	}
+}
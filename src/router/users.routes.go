@@ -2,32 +2,119 @@ package router
 
 import (
 	"net/http"
+	"time"
+	"users-api/src/auth"
 	"users-api/src/controllers"
+	"users-api/src/docs"
+	"users-api/src/metrics"
 	"users-api/src/middlewares"
+	"users-api/src/services"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine, userController *controllers.UserController, authController *controllers.AuthController) {
-	// Middleware para autenticación con API Key
-	router.Use(middlewares.APIKeyAuthMiddleware())
+// Controllers agrupa los controladores que exponen las rutas, para no crecer la firma de SetupRoutes con cada uno
+type Controllers struct {
+	User       *controllers.UserController
+	Auth       *controllers.AuthController
+	Cache      *controllers.CacheController
+	Health     *controllers.HealthController
+	Role       *controllers.RoleController
+	Group      *controllers.GroupController
+	Invitation *controllers.InvitationController
+	// GraphQL atiende /graphql, que no se versiona por prefijo sino evolucionando el esquema
+	GraphQL http.Handler
+}
 
-	// Configurar rutas para el servicio de usuarios
-	userRoutes := router.Group("/users")
-	{
-		userRoutes.GET("/", userController.GetUsers)
-		userRoutes.GET("/email/:email", userController.GetUserByEmail)
-		userRoutes.GET("/list", userController.GetUsersList)
-		userRoutes.GET("/:id", userController.GetUserByID)
-		userRoutes.POST("/", userController.CreateUser)
-		userRoutes.POST("/login", authController.Login)
-		userRoutes.PUT("/:id", userController.UpdateUser)
-		userRoutes.DELETE("/:id", userController.DeleteUser)
+// versions registra cada versión de la API bajo su prefijo. Una versión nueva agrega su función
+// acá y puede reutilizar los handlers que no cambian
+var versions = map[string]func(api *gin.RouterGroup, c *Controllers){
+	"v1": setupV1,
+}
+
+// LatestStableVersion es el destino de las rutas sin versión
+const LatestStableVersion = "v1"
+
+// Las rutas sin versión quedaron deprecadas al introducir /v1 y dejan de existir en LegacySunset
+var (
+	LegacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	LegacySunset       = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+func SetupRoutes(router *gin.Engine, credentials services.CredentialService, c *Controllers) {
+	// Rutas de salud sin autenticación para los probes de Kubernetes
+	router.GET("/healthz", c.Health.Liveness)
+	router.GET("/readyz", c.Health.Readiness)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Documentación pública del contrato
+	router.GET("/openapi.json", gin.WrapH(docs.SpecHandler()))
+	router.GET("/docs", gin.WrapH(docs.PageHandler()))
+
+	// Middleware para autenticación con API Key o token de acceso
+	apiKeyAuth := middlewares.AuthMiddleware(credentials)
+
+	for version, setup := range versions {
+		setup(router.Group("/"+version, apiKeyAuth), c)
 	}
 
+	graphqlHandler := gin.WrapH(c.GraphQL)
+	router.GET("/graphql", apiKeyAuth, graphqlHandler)
+	router.POST("/graphql", apiKeyAuth, graphqlHandler)
+
+	// Alias sin versión para los consumidores que todavía no migraron
+	legacy := router.Group("/", apiKeyAuth, middlewares.DeprecationMiddleware(LegacyDeprecatedAt, LegacySunset, "/"+LatestStableVersion))
+	versions[LatestStableVersion](legacy, c)
+
 	// Handler para rutas no encontradas
-	router.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Ruta no encontrada"})
+	router.NoRoute(apiKeyAuth, func(c *gin.Context) {
+		middlewares.ErrorResponse(c, http.StatusNotFound, "Ruta no encontrada")
 
 	})
 }
+
+func setupV1(api *gin.RouterGroup, c *Controllers) {
+	// Configurar rutas para el servicio de usuarios
+	userRoutes := api.Group("/users")
+	{
+		userRoutes.GET("/", c.User.GetUsers)
+		userRoutes.GET("/email/:email", c.User.GetUserByEmail)
+		userRoutes.GET("/list", c.User.GetUsersList)
+		userRoutes.GET("/:id", c.User.GetUserByID)
+		userRoutes.GET("/:id/groups", c.Group.ListUserGroups)
+		userRoutes.POST("/", c.User.CreateUser)
+		userRoutes.POST("/login", c.Auth.Login)
+		userRoutes.GET("/invitations", c.Invitation.ListInvitations)
+		userRoutes.POST("/invitations", c.Invitation.CreateInvitation)
+		userRoutes.POST("/invitations/accept", c.Invitation.AcceptInvitation)
+		userRoutes.DELETE("/invitations/:id", c.Invitation.RevokeInvitation)
+		userRoutes.PUT("/:id", c.User.UpdateUser)
+		userRoutes.PUT("/:id/status", c.User.UpdateUserStatus)
+		userRoutes.PUT("/:id/password", c.User.ChangePassword)
+		userRoutes.DELETE("/:id", c.User.DeleteUser)
+	}
+
+	api.GET("/roles", c.Role.ListRoles)
+
+	// Grupos de usuarios y sus miembros
+	groupRoutes := api.Group("/groups")
+	{
+		groupRoutes.GET("/", c.Group.ListGroups)
+		groupRoutes.POST("/", c.Group.CreateGroup)
+		groupRoutes.GET("/:id", c.Group.GetGroup)
+		groupRoutes.PUT("/:id", c.Group.UpdateGroup)
+		groupRoutes.DELETE("/:id", c.Group.DeleteGroup)
+		groupRoutes.GET("/:id/members", c.Group.ListMembers)
+		groupRoutes.POST("/:id/members", c.Group.AddMembers)
+		groupRoutes.DELETE("/:id/members", c.Group.RemoveMembers)
+	}
+
+	// Rutas de administración de la caché
+	adminRoutes := api.Group("/admin", middlewares.RequirePermission(auth.PermCacheManage))
+	{
+		adminRoutes.GET("/cache/stats", c.Cache.GetStats)
+		adminRoutes.POST("/cache/warmup", c.Cache.WarmUp)
+		adminRoutes.DELETE("/cache/users/:id", c.Cache.EvictUser)
+		adminRoutes.DELETE("/cache", c.Cache.Flush)
+	}
+}
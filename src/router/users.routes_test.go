@@ -0,0 +1,316 @@
+package router_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"users-api/src/apptest"
+	"users-api/src/cache"
+	"users-api/src/dto"
+	"users-api/src/router"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newUserBody(name string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      name,
+		"lastname":  "Test",
+		"birthdate": "1990-01-02T00:00:00Z",
+		"email":     name + "-" + uuid.NewString()[:8] + "@example.com",
+		"password":  "secreto123",
+	}
+}
+
+func createUser(t *testing.T, server *apptest.Server, name string) dto.UserResponseDTO {
+	t.Helper()
+	resp := server.Do(t, http.MethodPost, "/v1/users/", newUserBody(name))
+	require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+
+	var user dto.UserResponseDTO
+	resp.Decode(t, &user)
+	return user
+}
+
+func cacheStats(t *testing.T, server *apptest.Server) cache.Stats {
+	t.Helper()
+	resp := server.Do(t, http.MethodGet, "/v1/admin/cache/stats", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var stats cache.Stats
+	resp.Decode(t, &stats)
+	return stats
+}
+
+func TestAuth(t *testing.T) {
+	server := apptest.NewServer(t)
+
+	t.Run("sin clave responde 401", func(t *testing.T) {
+		resp := server.DoWithKey(t, "", http.MethodGet, "/v1/users/", nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("clave inválida responde 401", func(t *testing.T) {
+		resp := server.DoWithKey(t, "otra-clave", http.MethodPost, "/v1/users/", newUserBody("ana"))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("ruta inexistente también exige clave", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, "", http.MethodGet, "/nope", nil).StatusCode)
+		assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodGet, "/nope", nil).StatusCode)
+	})
+
+	t.Run("health y métricas no exigen clave", func(t *testing.T) {
+		for _, path := range []string{"/healthz", "/readyz", "/metrics"} {
+			assert.Equal(t, http.StatusOK, server.DoWithKey(t, "", http.MethodGet, path, nil).StatusCode, path)
+		}
+	})
+}
+
+func TestCreateUser(t *testing.T) {
+	server := apptest.NewServer(t)
+
+	t.Run("crea el usuario con valores por defecto y sin exponer la contraseña", func(t *testing.T) {
+		resp := server.Do(t, http.MethodPost, "/v1/users/", newUserBody("ana"))
+		require.Equal(t, http.StatusCreated, resp.StatusCode, "cuerpo: %s", resp.Body)
+		assert.NotContains(t, string(resp.Body), "password")
+
+		var user dto.UserResponseDTO
+		resp.Decode(t, &user)
+		assert.NotEmpty(t, user.ID)
+		assert.Equal(t, "user", user.Role)
+		assert.NotEmpty(t, user.Avatar)
+	})
+
+	for name, body := range map[string]interface{}{
+		"JSON mal formado":     `{"name":`,
+		"sin nombre":           map[string]interface{}{"lastname": "Test", "birthdate": "1990-01-02T00:00:00Z", "email": "a@example.com", "password": "secreto123"},
+		"email inválido":       map[string]interface{}{"name": "ana", "lastname": "Test", "birthdate": "1990-01-02T00:00:00Z", "email": "no-es-email", "password": "secreto123"},
+		"contraseña muy corta": map[string]interface{}{"name": "ana", "lastname": "Test", "birthdate": "1990-01-02T00:00:00Z", "email": "a@example.com", "password": "123"},
+	} {
+		t.Run(name+" responde 400", func(t *testing.T) {
+			resp := server.Do(t, http.MethodPost, "/v1/users/", body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "cuerpo: %s", resp.Body)
+			assert.NotEmpty(t, resp.Header.Get("X-Request-ID"))
+			assert.Contains(t, string(resp.Body), "request_id")
+		})
+	}
+}
+
+func TestGetUsers(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := createUser(t, server, "ana")
+	beto := createUser(t, server, "beto")
+
+	t.Run("lista todos los usuarios", func(t *testing.T) {
+		resp := server.Do(t, http.MethodGet, "/v1/users/", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var users []dto.UserResponseDTO
+		resp.Decode(t, &users)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("filtra por los campos del cuerpo", func(t *testing.T) {
+		var users []dto.UserResponseDTO
+		server.Do(t, http.MethodGet, "/v1/users/", map[string]string{"email": beto.Email}).Decode(t, &users)
+		require.Len(t, users, 1)
+		assert.Equal(t, beto.ID, users[0].ID)
+
+		server.Do(t, http.MethodGet, "/v1/users/", map[string]string{"name": ana.Name, "role": "admin"}).Decode(t, &users)
+		assert.Empty(t, users)
+	})
+
+	t.Run("filtro mal formado responde 400", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodGet, "/v1/users/", `{"name"`).StatusCode)
+	})
+
+	t.Run("por ID", func(t *testing.T) {
+		resp := server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var user dto.UserResponseDTO
+		resp.Decode(t, &user)
+		assert.Equal(t, ana, user)
+	})
+
+	t.Run("por email", func(t *testing.T) {
+		resp := server.Do(t, http.MethodGet, "/v1/users/email/"+beto.Email, nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var user dto.UserResponseDTO
+		resp.Decode(t, &user)
+		assert.Equal(t, beto, user)
+	})
+
+	t.Run("lista por IDs ignora los inexistentes", func(t *testing.T) {
+		resp := server.Do(t, http.MethodGet, "/v1/users/list", map[string]interface{}{"ids": []string{ana.ID, beto.ID, uuid.NewString()}})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var users []dto.UserResponseDTO
+		resp.Decode(t, &users)
+		assert.ElementsMatch(t, []string{ana.ID, beto.ID}, []string{users[0].ID, users[1].ID})
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("lista por IDs sin IDs responde 400", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodGet, "/v1/users/list", map[string]interface{}{"ids": []string{}}).StatusCode)
+		assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodGet, "/v1/users/list", map[string]interface{}{}).StatusCode)
+	})
+
+	t.Run("inexistentes responden 404", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodGet, "/v1/users/"+uuid.NewString(), nil).StatusCode)
+		assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodGet, "/v1/users/email/nadie@example.com", nil).StatusCode)
+	})
+}
+
+func TestLogin(t *testing.T) {
+	server := apptest.NewServer(t)
+	body := newUserBody("ana")
+	require.Equal(t, http.StatusCreated, server.Do(t, http.MethodPost, "/v1/users/", body).StatusCode)
+
+	t.Run("credenciales válidas", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			resp := server.Do(t, http.MethodPost, "/v1/users/login", map[string]interface{}{"email": body["email"], "password": body["password"]})
+			require.Equal(t, http.StatusOK, resp.StatusCode, "intento %d, cuerpo: %s", i, resp.Body)
+
+			var user dto.UserResponseDTO
+			resp.Decode(t, &user)
+			assert.Equal(t, body["email"], user.Email)
+		}
+	})
+
+	t.Run("contraseña incorrecta responde 401", func(t *testing.T) {
+		resp := server.Do(t, http.MethodPost, "/v1/users/login", map[string]interface{}{"email": body["email"], "password": "incorrecta"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("email inexistente responde igual que contraseña incorrecta", func(t *testing.T) {
+		resp := server.Do(t, http.MethodPost, "/v1/users/login", map[string]interface{}{"email": "nadie@example.com", "password": "incorrecta"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "INVALID CREDENTIALS")
+	})
+}
+
+func TestUpdateUser(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := createUser(t, server, "ana")
+
+	t.Run("actualiza solo los campos enviados", func(t *testing.T) {
+		resp := server.Do(t, http.MethodPut, "/v1/users/"+ana.ID, map[string]interface{}{"name": "Ana María"})
+		require.Equal(t, http.StatusOK, resp.StatusCode, "cuerpo: %s", resp.Body)
+
+		var user dto.UserResponseDTO
+		resp.Decode(t, &user)
+		assert.Equal(t, "Ana María", user.Name)
+		assert.Equal(t, ana.Lastname, user.Lastname)
+		assert.Equal(t, ana.Email, user.Email)
+	})
+
+	t.Run("JSON mal formado responde 400", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, server.Do(t, http.MethodPut, "/v1/users/"+ana.ID, `{"name":`).StatusCode)
+	})
+
+	t.Run("inexistente responde 404", func(t *testing.T) {
+		resp := server.Do(t, http.MethodPut, "/v1/users/"+uuid.NewString(), map[string]interface{}{"name": "x"})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, string(resp.Body), "USER_NOT_FOUND")
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := createUser(t, server, "ana")
+
+	resp := server.Do(t, http.MethodDelete, "/v1/users/"+ana.ID, nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+	assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodDelete, "/v1/users/"+ana.ID, nil).StatusCode)
+}
+
+func TestCache(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := createUser(t, server, "ana")
+
+	t.Run("la segunda lectura por ID sale de la caché", func(t *testing.T) {
+		before := cacheStats(t, server)
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+
+		after := cacheStats(t, server)
+		assert.Equal(t, before.Hits+1, after.Hits)
+		assert.Equal(t, before.Misses+1, after.Misses)
+		assert.Equal(t, int64(1), after.Keys["user_id"])
+	})
+
+	t.Run("actualizar invalida la caché", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/email/"+ana.Email, nil).StatusCode)
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodPut, "/v1/users/"+ana.ID, map[string]interface{}{"lastname": "Nueva"}).StatusCode)
+
+		stats := cacheStats(t, server)
+		assert.Zero(t, stats.Keys["user_id"])
+		assert.Zero(t, stats.Keys["user_email"])
+
+		var user dto.UserResponseDTO
+		server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).Decode(t, &user)
+		assert.Equal(t, "Nueva", user.Lastname)
+	})
+
+	t.Run("crear invalida el listado cacheado", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/", nil).StatusCode)
+		createUser(t, server, "beto")
+
+		var users []dto.UserResponseDTO
+		server.Do(t, http.MethodGet, "/v1/users/", nil).Decode(t, &users)
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("eliminar invalida la caché", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+		require.Equal(t, http.StatusNoContent, server.Do(t, http.MethodDelete, "/v1/users/"+ana.ID, nil).StatusCode)
+		assert.Equal(t, http.StatusNotFound, server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).StatusCode)
+	})
+
+	t.Run("flush vacía las claves del servicio", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodGet, "/v1/users/", nil).StatusCode)
+		require.Equal(t, http.StatusOK, server.Do(t, http.MethodDelete, "/v1/admin/cache", nil).StatusCode)
+		for keyspace, n := range cacheStats(t, server).Keys {
+			assert.Zero(t, n, keyspace)
+		}
+	})
+}
+
+func TestLegacyRoutes(t *testing.T) {
+	server := apptest.NewServer(t)
+	ana := createUser(t, server, "ana")
+
+	t.Run("las rutas sin versión responden igual con encabezados de deprecación", func(t *testing.T) {
+		legacy := server.Do(t, http.MethodGet, "/users/"+ana.ID, nil)
+		require.Equal(t, http.StatusOK, legacy.StatusCode)
+		assert.JSONEq(t, string(server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil).Body), string(legacy.Body))
+
+		assert.Equal(t, "@"+strconv.FormatInt(router.LegacyDeprecatedAt.Unix(), 10), legacy.Header.Get("Deprecation"))
+		assert.Equal(t, router.LegacySunset.Format(http.TimeFormat), legacy.Header.Get("Sunset"))
+		assert.Equal(t, `</v1/users/`+ana.ID+`>; rel="successor-version"`, legacy.Header.Get("Link"))
+	})
+
+	t.Run("las rutas versionadas no se marcan como deprecadas", func(t *testing.T) {
+		resp := server.Do(t, http.MethodGet, "/v1/users/"+ana.ID, nil)
+		assert.Empty(t, resp.Header.Get("Deprecation"))
+		assert.Empty(t, resp.Header.Get("Sunset"))
+	})
+
+	t.Run("los alias también exigen la API key", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, "", http.MethodGet, "/users/"+ana.ID, nil).StatusCode)
+		assert.Equal(t, http.StatusUnauthorized, server.DoWithKey(t, "", http.MethodPost, "/users/login", map[string]string{}).StatusCode)
+	})
+
+	t.Run("los errores de los alias mantienen el formato", func(t *testing.T) {
+		resp := server.Do(t, http.MethodPut, "/users/"+uuid.NewString(), map[string]interface{}{"name": "x"})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Sunset"))
+	})
+}
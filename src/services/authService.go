@@ -2,17 +2,19 @@ package services
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"time"
+	stderrors "errors"
+	"users-api/src/config/log"
 
+	"users-api/src/cache"
 	"users-api/src/client"
+	"users-api/src/config/tracing"
 	"users-api/src/dto"
 	"users-api/src/errors"
+	"users-api/src/metrics"
 	"users-api/src/utils"
 
-	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 type AuthService interface {
@@ -20,72 +22,124 @@ type AuthService interface {
 }
 
 type authService struct {
-	repo        client.UserRepository
-	redisClient *redis.Client
-	logger      *zap.Logger
+	repo   client.UserRepository
+	hasher utils.PasswordHasher
+	cache  cache.Cache
+	logger *zap.Logger
 }
 
-func NewAuthService(repo client.UserRepository, redisClient *redis.Client, logger *zap.Logger) AuthService {
+func NewAuthService(repo client.UserRepository, hasher utils.PasswordHasher, cache cache.Cache, logger *zap.Logger) AuthService {
 	return &authService{
-		repo:        repo,
-		redisClient: redisClient,
-		logger:      logger,
+		repo:   repo,
+		hasher: hasher,
+		cache:  cache,
+		logger: logger,
 	}
 }
 
 func (s *authService) Login(ctx context.Context, loginDTO *dto.LoginDTO) (*dto.UserResponseDTO, error) {
-	cacheKey := fmt.Sprintf("user_email:%s", loginDTO.Email)
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+	logger.Info("[USERS-API]: Iniciando login", zap.String("email", loginDTO.Email))
+
+	cacheKey := cache.AuthEmailKey(tenantID(ctx), loginDTO.Email)
 	var user *dto.UserDTO
 
-	if s.redisClient != nil {
-		// Intentar obtener de caché
-		cachedUser, err := s.redisClient.Get(ctx, cacheKey).Result()
-		if err == nil {
-			err = json.Unmarshal([]byte(cachedUser), &user)
-			if err == nil {
-				// Verificar la contraseña
-				if !utils.CheckPasswordHash(loginDTO.Password, user.Password) {
-					return nil, errors.NewError("INVALID CREDENTIALS", "Invalid credentials", 401)
-				}
-				return &dto.UserResponseDTO{
-					ID:        user.ID,
-					Name:      user.Name,
-					Lastname:  user.Lastname,
-					Birthdate: user.Birthdate,
-					Role:      user.Role,
-					Email:     user.Email,
-					Avatar:    user.Avatar,
-				}, nil
-			}
+	// Intentar obtener de caché
+	if err := s.cache.Get(ctx, cacheKey, &user); err == nil && user != nil {
+		metrics.ObserveCache("auth_email", true)
+		// Verificar la contraseña
+		ok, needsRehash := s.hasher.Verify(loginDTO.Password, user.Password)
+		if !ok {
+			metrics.ObserveLogin(false)
+			logger.Warn("[USERS-API]: Credenciales inválidas", zap.String("id", user.ID))
+			return nil, errors.NewError("INVALID CREDENTIALS", "Invalid credentials", 401)
+		}
+		if err := accountStatusError(user.Status); err != nil {
+			metrics.ObserveLogin(false)
+			logger.Warn("[USERS-API]: Login de una cuenta no activa", zap.String("id", user.ID), zap.String("status", user.Status))
+			return nil, err
 		}
+		metrics.ObserveLogin(true)
+		if needsRehash {
+			s.rehash(ctx, user.ID, loginDTO.Email, user.Password, loginDTO.Password)
+		}
+		return &dto.UserResponseDTO{
+			ID:                user.ID,
+			Name:              user.Name,
+			Lastname:          user.Lastname,
+			Birthdate:         user.Birthdate,
+			Role:              user.Role,
+			Email:             user.Email,
+			Avatar:            user.Avatar,
+			TenantID:          tenantID(ctx),
+			Status:            userStatus(user.Status),
+			PasswordChangedAt: user.PasswordChangedAt,
+		}, nil
 	}
 
+	metrics.ObserveCache("auth_email", false)
+
 	// Si no está en caché o Redis no está disponible, obtener de la base de datos
 	dbUser, err := s.repo.ReadByEmail(ctx, loginDTO.Email)
 	if err != nil {
+		metrics.ObserveLogin(false)
+		logger.Warn("[USERS-API]: Usuario no encontrado para login", zap.String("email", loginDTO.Email), zap.Error(err))
+		tracing.RecordError(span, err)
+		// Un email inexistente responde igual que una contraseña incorrecta para no revelar qué cuentas existen
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.NewError("INVALID CREDENTIALS", "Invalid credentials", 401)
+		}
 		return nil, err
 	}
 
 	// Verificar la contraseña
-	if !utils.CheckPasswordHash(loginDTO.Password, dbUser.Password) {
+	ok, needsRehash := s.hasher.Verify(loginDTO.Password, dbUser.Password)
+	if !ok {
+		metrics.ObserveLogin(false)
+		logger.Warn("[USERS-API]: Credenciales inválidas", zap.String("id", dbUser.ID))
 		return nil, errors.NewError("INVALID CREDENTIALS", "Invalid credentials", 401)
 	}
 
-	userResponse := &dto.UserResponseDTO{
-		ID:        dbUser.ID,
-		Name:      dbUser.Name,
-		Lastname:  dbUser.Lastname,
-		Birthdate: dbUser.Birthdate,
-		Role:      dbUser.Role,
-		Email:     dbUser.Email,
-		Avatar:    dbUser.Avatar,
-	}
+	// Guardar en caché, también si la cuenta no está activa: el cambio de estado invalida la clave
+	s.cache.Set(ctx, cacheKey, dbUser, cache.DefaultTTL)
 
-	if s.redisClient != nil {
-		// Guardar en caché
-		userJSON, _ := json.Marshal(dbUser)
-		s.redisClient.Set(ctx, cacheKey, userJSON, 5*time.Minute)
+	// El estado se informa solo con la contraseña correcta, para no revelar qué cuentas existen
+	if err := accountStatusError(dbUser.Status); err != nil {
+		metrics.ObserveLogin(false)
+		logger.Warn("[USERS-API]: Login de una cuenta no activa", zap.String("id", dbUser.ID), zap.String("status", dbUser.Status))
+		return nil, err
+	}
+	metrics.ObserveLogin(true)
+	if needsRehash {
+		s.rehash(ctx, dbUser.ID, dbUser.Email, dbUser.Password, loginDTO.Password)
 	}
 
+	userResponse := newUserResponseDTO(dbUser)
+
 	return userResponse, nil
 }
+
+// rehash reemplaza un hash de otro algoritmo o con otros parámetros por uno actual, aprovechando que
+// en el login se conoce la contraseña. Si falla, el login sigue siendo válido y se reintenta en el próximo
+func (s *authService) rehash(ctx context.Context, id, email, current, password string) {
+	ctx, span := tracer.Start(ctx, "AuthService.rehash")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		logger.Warn("[USERS-API]: No se pudo rehashear la contraseña", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return
+	}
+	// Si la contraseña cambió desde que se leyó, no se pisa el cambio
+	if err := s.repo.UpdatePassword(ctx, id, current, hash); err != nil {
+		logger.Warn("[USERS-API]: No se pudo guardar la contraseña rehasheada", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return
+	}
+	s.cache.Delete(ctx, cache.AuthEmailKey(tenantID(ctx), email))
+	logger.Info("[USERS-API]: Contraseña rehasheada con el algoritmo actual", zap.String("id", id))
+}
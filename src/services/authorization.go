@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"users-api/src/auth"
+	"users-api/src/config/log"
+	"users-api/src/errors"
+
+	"go.uber.org/zap"
+)
+
+// authorize verifica que el principal de ctx tenga permission. Una llamada sin principal se
+// rechaza, los comandos administrativos usan auth.System
+func authorize(ctx context.Context, logger *zap.Logger, permission string) error {
+	principal := auth.FromContext(ctx)
+	if principal.Can(permission) {
+		return nil
+	}
+	return forbidden(ctx, logger, principal, permission)
+}
+
+// authorizeUser verifica que el principal de ctx pueda operar sobre userID, con anyPermission
+// o con selfPermission si es su propia cuenta
+func authorizeUser(ctx context.Context, logger *zap.Logger, userID, anyPermission, selfPermission string) error {
+	principal := auth.FromContext(ctx)
+	if principal.CanActOn(userID, anyPermission, selfPermission) {
+		return nil
+	}
+	return forbidden(ctx, logger, principal, anyPermission)
+}
+
+func forbidden(ctx context.Context, logger *zap.Logger, principal *auth.Principal, permission string) error {
+	fields := []zap.Field{zap.String("permission", permission)}
+	if principal != nil {
+		fields = append(fields, zap.String("kind", principal.Kind), zap.String("role", principal.Role))
+	}
+	log.FromContext(ctx, logger).Warn("[USERS-API]: Operación no permitida", fields...)
+	return errors.ErrForbidden
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"users-api/src/cache"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/errors"
+
+	"go.uber.org/zap"
+)
+
+// DefaultWarmUpLimit es la cantidad de usuarios que se precargan si no se indica otra
+const DefaultWarmUpLimit = 100
+
+type CacheService interface {
+	WarmUp(ctx context.Context, limit int) (int, error)
+	Stats(ctx context.Context) (*cache.Stats, error)
+	EvictUser(ctx context.Context, id string) error
+	Flush(ctx context.Context) (int64, error)
+}
+
+type cacheService struct {
+	repo   client.UserRepository
+	cache  cache.Cache
+	logger *zap.Logger
+}
+
+func NewCacheService(repo client.UserRepository, cache cache.Cache, logger *zap.Logger) CacheService {
+	return &cacheService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// WarmUp precarga en caché los usuarios con actividad más reciente de la organización de ctx
+func (s *cacheService) WarmUp(ctx context.Context, limit int) (int, error) {
+	ctx, span := tracer.Start(ctx, "CacheService.WarmUp")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if !s.cache.Enabled() {
+		return 0, errors.ErrCacheUnavailable
+	}
+	if limit <= 0 {
+		limit = DefaultWarmUpLimit
+	}
+
+	logger.Info("[USERS-API]: Iniciando precarga de caché", zap.Int("limit", limit))
+
+	users, err := s.repo.ReadRecent(ctx, limit)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al obtener usuarios para precargar caché", zap.Error(err))
+		tracing.RecordError(span, err)
+		return 0, err
+	}
+
+	warmed := 0
+	for i := range users {
+		userResponse := newUserResponseDTO(&users[i])
+		if err := s.cache.Set(ctx, cache.UserIDKey(users[i].TenantID, users[i].ID), userResponse, cache.DefaultTTL); err != nil {
+			logger.Error("[USERS-API]: Error al precargar usuario en caché", zap.String("id", users[i].ID), zap.Error(err))
+			tracing.RecordError(span, err)
+			return warmed, err
+		}
+		if err := s.cache.Set(ctx, cache.UserEmailKey(users[i].TenantID, users[i].Email), userResponse, cache.DefaultTTL); err != nil {
+			logger.Error("[USERS-API]: Error al precargar usuario en caché", zap.String("id", users[i].ID), zap.Error(err))
+			tracing.RecordError(span, err)
+			return warmed, err
+		}
+		warmed++
+	}
+
+	logger.Info("[USERS-API]: Caché precargada exitosamente", zap.Int("count", warmed))
+	return warmed, nil
+}
+
+func (s *cacheService) Stats(ctx context.Context) (*cache.Stats, error) {
+	ctx, span := tracer.Start(ctx, "CacheService.Stats")
+	defer span.End()
+
+	return s.cache.Stats(ctx, tenantID(ctx))
+}
+
+// EvictUser elimina de caché todas las entradas de un usuario
+func (s *cacheService) EvictUser(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "CacheService.EvictUser")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Eliminando usuario de caché", zap.String("id", id))
+
+	keys := []string{cache.AllUsersKey(tenantID(ctx)), cache.UserIDKey(tenantID(ctx), id)}
+
+	// Las claves por email solo se conocen si el usuario sigue existiendo
+	user, err := s.repo.ReadOne(ctx, id)
+	if err == nil {
+		keys = userCacheKeys(user)
+	}
+
+	if err := s.cache.Delete(ctx, keys...); err != nil {
+		logger.Error("[USERS-API]: Error al eliminar usuario de caché", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
+
+func (s *cacheService) Flush(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "CacheService.Flush")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Vaciando caché de usuarios")
+
+	// Solo las claves de la organización de ctx: cualquier API key tiene cache.manage
+	deleted, err := s.cache.Flush(ctx, tenantID(ctx))
+	if err != nil {
+		logger.Error("[USERS-API]: Error al vaciar caché", zap.Error(err))
+		tracing.RecordError(span, err)
+		return deleted, err
+	}
+
+	logger.Info("[USERS-API]: Caché vaciada exitosamente", zap.Int64("deleted", deleted))
+	return deleted, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	stderrors "errors"
+	"strings"
+	"users-api/src/auth"
+	"users-api/src/cache"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/metrics"
+	"users-api/src/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// CredentialService identifica al principal de una solicitud a partir del encabezado Authorization
+// (HTTP) o del metadata authorization (gRPC), para que ambos transportes autentiquen igual
+type CredentialService interface {
+	// Authenticate acepta una API key, que identifica a auth.Service en la organización de la key, o
+	// "Bearer <token>" con un token de acceso, que identifica al usuario con los permisos de su rol
+	// si su cuenta sigue activa y no cambió la contraseña desde que se emitió. La API key de la configuración corresponde a models.DefaultOrganizationID
+	Authenticate(ctx context.Context, credential string) (*auth.Principal, error)
+}
+
+type credentialService struct {
+	apiKey              string
+	tokenService        TokenService
+	roleService         RoleService
+	organizationService OrganizationService
+	users               client.UserRepository
+	cache               cache.Cache
+	logger              *zap.Logger
+}
+
+func NewCredentialService(apiKey string, tokenService TokenService, roleService RoleService, organizationService OrganizationService, users client.UserRepository, cache cache.Cache, logger *zap.Logger) CredentialService {
+	return &credentialService{
+		apiKey:              apiKey,
+		tokenService:        tokenService,
+		roleService:         roleService,
+		organizationService: organizationService,
+		users:               users,
+		cache:               cache,
+		logger:              logger,
+	}
+}
+
+func (s *credentialService) Authenticate(ctx context.Context, credential string) (*auth.Principal, error) {
+	token, ok := strings.CutPrefix(credential, "Bearer ")
+	if !ok {
+		if credential == "" {
+			return nil, errors.ErrInvalidAPIKey
+		}
+		if subtle.ConstantTimeCompare([]byte(credential), []byte(s.apiKey)) == 1 {
+			return auth.Service(models.DefaultOrganizationID), nil
+		}
+		tenantID, err := s.organizationService.ResolveAPIKey(ctx, credential)
+		if err != nil {
+			return nil, err
+		}
+		return auth.Service(tenantID), nil
+	}
+
+	claims, err := s.tokenService.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Los permisos se resuelven en cada solicitud para que un cambio en role_permissions aplique
+	// sin reemitir tokens. El rol en sí viaja en el token hasta que vence
+	tenantID := claims.TenantID
+	if tenantID == "" {
+		tenantID = models.DefaultOrganizationID
+	}
+	principal := &auth.Principal{Kind: auth.KindUser, TenantID: tenantID, UserID: claims.Subject, Email: claims.Email, Role: claims.Role, Groups: claims.Groups}
+	if err := s.checkAccount(ctx, principal, claims.PasswordChangedAt); err != nil {
+		return nil, err
+	}
+	role, err := s.roleService.GetRole(ctx, claims.Role)
+	switch {
+	case err == nil:
+		principal.Permissions = role.Permissions
+	case stderrors.Is(err, errors.ErrInvalidRole):
+		log.FromContext(ctx, s.logger).Warn("[USERS-API]: Token con un rol inexistente, sin permisos", zap.String("role", claims.Role))
+	default:
+		return nil, err
+	}
+	return principal, nil
+}
+
+// checkAccount rechaza los tokens de cuentas que dejaron de estar activas o se eliminaron, y los
+// emitidos antes del último cambio de contraseña. Lee al usuario de la misma clave de caché que
+// GetUserByID, que los cambios de estado y de contraseña invalidan
+func (s *credentialService) checkAccount(ctx context.Context, principal *auth.Principal, passwordChangedAt int64) error {
+	logger := log.FromContext(ctx, s.logger)
+	cacheKey := cache.UserIDKey(principal.TenantID, principal.UserID)
+
+	var user *dto.UserResponseDTO
+	if err := s.cache.Get(ctx, cacheKey, &user); err == nil && user != nil {
+		metrics.ObserveCache("user_id", true)
+	} else {
+		metrics.ObserveCache("user_id", false)
+
+		dbUser, err := s.users.ReadOne(auth.WithPrincipal(ctx, auth.System(principal.TenantID)), principal.UserID)
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("[USERS-API]: Token de un usuario eliminado", zap.String("id", principal.UserID))
+			return errors.ErrInvalidToken
+		}
+		if err != nil {
+			return err
+		}
+		user = newUserResponseDTO(dbUser)
+		s.cache.Set(ctx, cacheKey, user, cache.DefaultTTL)
+	}
+
+	if unixMicro(user.PasswordChangedAt) != passwordChangedAt {
+		logger.Warn("[USERS-API]: Token emitido antes del último cambio de contraseña", zap.String("id", principal.UserID))
+		return errors.ErrInvalidToken
+	}
+	if err := accountStatusError(user.Status); err != nil {
+		logger.Warn("[USERS-API]: Token de una cuenta no activa",
+			zap.String("id", principal.UserID), zap.String("status", user.Status))
+		return err
+	}
+	return nil
+}
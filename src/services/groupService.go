@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"users-api/src/auth"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/models"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type GroupService interface {
+	CreateGroup(ctx context.Context, createGroupDTO *dto.CreateGroupDTO) (*dto.GroupDTO, error)
+	ListGroups(ctx context.Context, page dto.PageQuery) (*dto.GroupPageDTO, error)
+	GetGroup(ctx context.Context, id string) (*dto.GroupDTO, error)
+	UpdateGroup(ctx context.Context, id string, updateGroupDTO *dto.UpdateGroupDTO) (*dto.GroupDTO, error)
+	DeleteGroup(ctx context.Context, id string) error
+	// AddMembers agrega los usuarios al grupo; si alguno no existe en la organización no agrega ninguno
+	AddMembers(ctx context.Context, groupID string, userIDs []string) (*dto.GroupMembersResultDTO, error)
+	RemoveMembers(ctx context.Context, groupID string, userIDs []string) (*dto.GroupMembersResultDTO, error)
+	ListMembers(ctx context.Context, groupID string, page dto.PageQuery) (*dto.UserPageDTO, error)
+	// ListUserGroups devuelve los grupos de un usuario; cada usuario puede consultar los suyos
+	ListUserGroups(ctx context.Context, userID string, page dto.PageQuery) (*dto.GroupPageDTO, error)
+}
+
+type groupService struct {
+	groups      client.GroupRepository
+	memberships client.MembershipRepository
+	users       client.UserRepository
+	logger      *zap.Logger
+}
+
+func NewGroupService(groups client.GroupRepository, memberships client.MembershipRepository, users client.UserRepository, logger *zap.Logger) GroupService {
+	return &groupService{
+		groups:      groups,
+		memberships: memberships,
+		users:       users,
+		logger:      logger,
+	}
+}
+
+func newGroupDTO(group *models.Group) *dto.GroupDTO {
+	return &dto.GroupDTO{
+		ID:          group.ID,
+		Name:        group.Name,
+		Description: group.Description,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+}
+
+func newGroupPageDTO(groups []models.Group, total int64, page dto.PageQuery) *dto.GroupPageDTO {
+	groupResponses := make([]dto.GroupDTO, 0, len(groups))
+	for i := range groups {
+		groupResponses = append(groupResponses, *newGroupDTO(&groups[i]))
+	}
+	return &dto.GroupPageDTO{
+		Groups:  groupResponses,
+		PageDTO: dto.PageDTO{Total: total, Limit: page.Limit, Offset: page.Offset},
+	}
+}
+
+// readGroup traduce el not found del repositorio al error de aplicación
+func (s *groupService) readGroup(ctx context.Context, id string) (*models.Group, error) {
+	group, err := s.groups.ReadOne(ctx, id)
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.ErrGroupNotFound
+	}
+	return group, err
+}
+
+// checkNameAvailable verifica que ningún otro grupo de la organización use name
+func (s *groupService) checkNameAvailable(ctx context.Context, name, exceptID string) error {
+	existing, err := s.groups.ReadByName(ctx, name)
+	switch {
+	case err == nil && existing.ID != exceptID:
+		return errors.ErrGroupExists
+	case err == nil, stderrors.Is(err, gorm.ErrRecordNotFound):
+		return nil
+	default:
+		return err
+	}
+}
+
+func (s *groupService) CreateGroup(ctx context.Context, createGroupDTO *dto.CreateGroupDTO) (*dto.GroupDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.CreateGroup")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsManage); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, createGroupDTO.Name, ""); err != nil {
+		return nil, err
+	}
+
+	group := &models.Group{
+		ID:          uuid.New().String(),
+		Name:        createGroupDTO.Name,
+		Description: createGroupDTO.Description,
+	}
+	if err := s.groups.Create(ctx, group); err != nil {
+		logger.Error("[USERS-API]: Error al crear grupo", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API]: Grupo creado exitosamente", zap.String("id", group.ID))
+	return newGroupDTO(group), nil
+}
+
+func (s *groupService) ListGroups(ctx context.Context, page dto.PageQuery) (*dto.GroupPageDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.ListGroups")
+	defer span.End()
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsRead); err != nil {
+		return nil, err
+	}
+
+	page = page.Normalize()
+	groups, total, err := s.groups.ReadPage(ctx, page.Limit, page.Offset)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener grupos", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return newGroupPageDTO(groups, total, page), nil
+}
+
+func (s *groupService) GetGroup(ctx context.Context, id string) (*dto.GroupDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.GetGroup")
+	defer span.End()
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsRead); err != nil {
+		return nil, err
+	}
+
+	group, err := s.readGroup(ctx, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	return newGroupDTO(group), nil
+}
+
+func (s *groupService) UpdateGroup(ctx context.Context, id string, updateGroupDTO *dto.UpdateGroupDTO) (*dto.GroupDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.UpdateGroup")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsManage); err != nil {
+		return nil, err
+	}
+
+	group, err := s.readGroup(ctx, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	if updateGroupDTO.Name != nil && *updateGroupDTO.Name != group.Name {
+		if err := s.checkNameAvailable(ctx, *updateGroupDTO.Name, id); err != nil {
+			return nil, err
+		}
+		group.Name = *updateGroupDTO.Name
+	}
+	if updateGroupDTO.Description != nil {
+		group.Description = *updateGroupDTO.Description
+	}
+
+	if err := s.groups.Update(ctx, id, group); err != nil {
+		logger.Error("[USERS-API]: Error al actualizar grupo", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API]: Grupo actualizado exitosamente", zap.String("id", id))
+	return s.GetGroup(ctx, id)
+}
+
+func (s *groupService) DeleteGroup(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "GroupService.DeleteGroup")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsManage); err != nil {
+		return err
+	}
+	if _, err := s.readGroup(ctx, id); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	if err := s.groups.Delete(ctx, id); err != nil {
+		logger.Error("[USERS-API]: Error al eliminar grupo", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API]: Grupo eliminado exitosamente", zap.String("id", id))
+	return nil
+}
+
+func (s *groupService) AddMembers(ctx context.Context, groupID string, userIDs []string) (*dto.GroupMembersResultDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.AddMembers")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsManage); err != nil {
+		return nil, err
+	}
+	if _, err := s.readGroup(ctx, groupID); err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	userIDs = uniqueStrings(userIDs)
+	users, err := s.users.GetUsersList(ctx, userIDs)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al verificar los usuarios a agregar", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	if len(users) != len(userIDs) {
+		logger.Warn("[USERS-API]: Usuarios inexistentes al agregar miembros", zap.String("group_id", groupID),
+			zap.Int("requested", len(userIDs)), zap.Int("found", len(users)))
+		return nil, errors.ErrUnknownMembers
+	}
+
+	added, err := s.memberships.AddMembers(ctx, groupID, userIDs)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al agregar miembros", zap.String("group_id", groupID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API]: Miembros agregados", zap.String("group_id", groupID), zap.Int64("added", added))
+	return &dto.GroupMembersResultDTO{Added: added}, nil
+}
+
+func (s *groupService) RemoveMembers(ctx context.Context, groupID string, userIDs []string) (*dto.GroupMembersResultDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.RemoveMembers")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsManage); err != nil {
+		return nil, err
+	}
+	if _, err := s.readGroup(ctx, groupID); err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	removed, err := s.memberships.RemoveMembers(ctx, groupID, uniqueStrings(userIDs))
+	if err != nil {
+		logger.Error("[USERS-API]: Error al quitar miembros", zap.String("group_id", groupID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	logger.Info("[USERS-API]: Miembros quitados", zap.String("group_id", groupID), zap.Int64("removed", removed))
+	return &dto.GroupMembersResultDTO{Removed: removed}, nil
+}
+
+func (s *groupService) ListMembers(ctx context.Context, groupID string, page dto.PageQuery) (*dto.UserPageDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.ListMembers")
+	defer span.End()
+
+	if err := authorize(ctx, s.logger, auth.PermGroupsRead); err != nil {
+		return nil, err
+	}
+	if _, err := s.readGroup(ctx, groupID); err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	page = page.Normalize()
+	userIDs, total, err := s.memberships.ReadMemberIDs(ctx, groupID, page.Limit, page.Offset)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener miembros", zap.String("group_id", groupID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	userResponses := make([]dto.UserResponseDTO, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		users, err := s.users.GetUsersList(ctx, userIDs)
+		if err != nil {
+			tracing.RecordError(span, err)
+			return nil, err
+		}
+		// GetUsersList no conserva el orden de los IDs, la página sigue el orden de ingreso al grupo
+		byID := make(map[string]*models.User, len(users))
+		for i := range users {
+			byID[users[i].ID] = &users[i]
+		}
+		for _, id := range userIDs {
+			if user, ok := byID[id]; ok {
+				userResponses = append(userResponses, *newUserResponseDTO(user))
+			}
+		}
+	}
+
+	return &dto.UserPageDTO{
+		Users:   userResponses,
+		PageDTO: dto.PageDTO{Total: total, Limit: page.Limit, Offset: page.Offset},
+	}, nil
+}
+
+func (s *groupService) ListUserGroups(ctx context.Context, userID string, page dto.PageQuery) (*dto.GroupPageDTO, error) {
+	ctx, span := tracer.Start(ctx, "GroupService.ListUserGroups")
+	defer span.End()
+
+	if err := authorizeUser(ctx, s.logger, userID, auth.PermUsersReadAny, auth.PermUsersReadSelf); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.ReadOne(ctx, userID); err != nil {
+		tracing.RecordError(span, err)
+		return nil, notFoundAsUserError(err)
+	}
+
+	page = page.Normalize()
+	groupIDs, err := s.memberships.ReadGroupIDs(ctx, userID)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener grupos del usuario", zap.String("user_id", userID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	// Un usuario pertenece a pocos grupos, así que se leen todos y se pagina por nombre acá
+	var groups []models.Group
+	if len(groupIDs) > 0 {
+		if groups, err = s.groups.ReadList(ctx, groupIDs); err != nil {
+			tracing.RecordError(span, err)
+			return nil, err
+		}
+	}
+	total := int64(len(groups))
+	if page.Offset >= len(groups) {
+		groups = nil
+	} else {
+		groups = groups[page.Offset:min(len(groups), page.Offset+page.Limit)]
+	}
+	return newGroupPageDTO(groups, total, page), nil
+}
+
+// uniqueStrings quita los repetidos conservando el orden
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+	"users-api/src/config/log"
+
+	"go.uber.org/zap"
+)
+
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusFail     = "fail"
+)
+
+// healthCheckTimeout limita cuánto puede tardar cada dependencia en responder
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck verifica una dependencia. Si no es crítica, su falla solo degrada el servicio
+type HealthCheck struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+type HealthCheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status string                       `json:"status"`
+	Checks map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+type HealthService interface {
+	Liveness(ctx context.Context) *HealthReport
+	Readiness(ctx context.Context) *HealthReport
+}
+
+type healthService struct {
+	checks []HealthCheck
+	logger *zap.Logger
+}
+
+func NewHealthService(checks []HealthCheck, logger *zap.Logger) HealthService {
+	return &healthService{
+		checks: checks,
+		logger: logger,
+	}
+}
+
+// Liveness solo indica que el proceso está vivo, no consulta dependencias
+func (s *healthService) Liveness(ctx context.Context) *HealthReport {
+	return &HealthReport{Status: HealthStatusOK}
+}
+
+// Readiness consulta todas las dependencias en paralelo
+func (s *healthService) Readiness(ctx context.Context) *HealthReport {
+	logger := log.FromContext(ctx, s.logger)
+	report := &HealthReport{
+		Status: HealthStatusOK,
+		Checks: make(map[string]HealthCheckResult, len(s.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+			result := s.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			switch {
+			case result.Status == HealthStatusFail:
+				report.Status = HealthStatusFail
+			case result.Status == HealthStatusDegraded && report.Status == HealthStatusOK:
+				report.Status = HealthStatusDegraded
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	if report.Status != HealthStatusOK {
+		logger.Warn("[USERS-API]: Servicio no está completamente listo", zap.String("status", report.Status), zap.Any("checks", report.Checks))
+	}
+
+	return report
+}
+
+func (s *healthService) run(ctx context.Context, check HealthCheck) HealthCheckResult {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := HealthCheckResult{
+		Status:    HealthStatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		if check.Critical {
+			result.Status = HealthStatusFail
+		} else {
+			result.Status = HealthStatusDegraded
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"time"
+	"users-api/src/auth"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/models"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// invitationIssuer distingue los tokens de invitación de los de acceso, que TokenService rechaza por el emisor
+const invitationIssuer = "users-api/invitations"
+
+// MaxInvitationTTL es el vencimiento más lejano que se puede pedir para una invitación
+const MaxInvitationTTL = 30 * 24 * time.Hour
+
+// invitationClaims es lo que lleva firmado el token de invitación. El email y el rol se leen de la
+// invitación guardada, así revocarla invalida el token
+type invitationClaims struct {
+	TenantID string `json:"tid"`
+	jwt.RegisteredClaims
+}
+
+type InvitationService interface {
+	// CreateInvitation guarda la invitación y envía el token al invitado con el Notifier
+	CreateInvitation(ctx context.Context, createInvitationDTO *dto.CreateInvitationDTO) (*dto.InvitationDTO, error)
+	ListInvitations(ctx context.Context, page dto.PageQuery) (*dto.InvitationPageDTO, error)
+	RevokeInvitation(ctx context.Context, id string) error
+	// AcceptInvitation crea el usuario invitado con UserService.CreateUser y el rol de la invitación
+	AcceptInvitation(ctx context.Context, acceptInvitationDTO *dto.AcceptInvitationDTO) (*dto.UserResponseDTO, error)
+}
+
+type invitationService struct {
+	repo     client.InvitationRepository
+	users    client.UserRepository
+	service  UserService
+	roles    RoleService
+	notifier Notifier
+	secret   []byte
+	ttl      time.Duration
+	logger   *zap.Logger
+}
+
+func NewInvitationService(repo client.InvitationRepository, users client.UserRepository, service UserService, roles RoleService, notifier Notifier, secret string, ttl time.Duration, logger *zap.Logger) InvitationService {
+	return &invitationService{
+		repo:     repo,
+		users:    users,
+		service:  service,
+		roles:    roles,
+		notifier: notifier,
+		secret:   []byte(secret),
+		ttl:      ttl,
+		logger:   logger,
+	}
+}
+
+func newInvitationDTO(invitation *models.Invitation) *dto.InvitationDTO {
+	return &dto.InvitationDTO{
+		ID:        invitation.ID,
+		TenantID:  invitation.TenantID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		InvitedBy: invitation.InvitedBy,
+		ExpiresAt: invitation.ExpiresAt,
+		CreatedAt: invitation.CreatedAt,
+	}
+}
+
+// utcNow es la hora en UTC para que SQLite, que compara las fechas como texto, ordene igual que PostgreSQL
+func utcNow() time.Time {
+	return time.Now().UTC()
+}
+
+// invitedBy identifica en la invitación a quien la creó: el ID del usuario o el tipo de principal
+func invitedBy(principal *auth.Principal) string {
+	if principal.UserID != "" {
+		return principal.UserID
+	}
+	return principal.Kind
+}
+
+// checkEmailAvailable verifica que no haya un usuario ni otra invitación pendiente con email
+func (s *invitationService) checkEmailAvailable(ctx context.Context, email string, at time.Time) error {
+	if _, err := s.users.ReadByEmail(ctx, email); err == nil {
+		return errors.ErrUserExists
+	} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if _, err := s.repo.ReadPendingByEmail(ctx, email, at); err == nil {
+		return errors.ErrInvitationExists
+	} else if !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (s *invitationService) sign(invitation *models.Invitation) (string, error) {
+	claims := &invitationClaims{
+		TenantID: invitation.TenantID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        invitation.ID,
+			Issuer:    invitationIssuer,
+			IssuedAt:  jwt.NewNumericDate(invitation.CreatedAt),
+			ExpiresAt: jwt.NewNumericDate(invitation.ExpiresAt),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+}
+
+// verify devuelve los datos del token de invitación si la firma es válida y no venció
+func (s *invitationService) verify(ctx context.Context, token string) (*invitationClaims, error) {
+	claims := &invitationClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(invitationIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		log.FromContext(ctx, s.logger).Warn("[USERS-API]: Token de invitación rechazado", zap.Error(err))
+		return nil, errors.ErrInvalidInvitation
+	}
+	return claims, nil
+}
+
+func (s *invitationService) CreateInvitation(ctx context.Context, createInvitationDTO *dto.CreateInvitationDTO) (*dto.InvitationDTO, error) {
+	ctx, span := tracer.Start(ctx, "InvitationService.CreateInvitation")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando creación de invitación", zap.String("email", createInvitationDTO.Email))
+	if err := authorize(ctx, s.logger, auth.PermUsersWriteAny); err != nil {
+		return nil, err
+	}
+	if len(s.secret) == 0 {
+		return nil, errors.ErrInvitationsDisabled
+	}
+
+	role := strings.TrimSpace(createInvitationDTO.Role)
+	if role == "" {
+		role = auth.DefaultRole
+	}
+	// Invitar con un rol distinto del por defecto es asignarlo, igual que en UserService.CreateUser
+	if role != auth.DefaultRole {
+		if err := authorize(ctx, s.logger, auth.PermRolesAssign); err != nil {
+			return nil, err
+		}
+		if _, err := s.roles.GetRole(ctx, role); err != nil {
+			return nil, err
+		}
+	}
+
+	createdAt := utcNow()
+	expiresAt := createdAt.Add(s.ttl)
+	if createInvitationDTO.ExpiresAt != nil {
+		expiresAt = createInvitationDTO.ExpiresAt.UTC()
+		if !expiresAt.After(createdAt) || expiresAt.Sub(createdAt) > MaxInvitationTTL {
+			return nil, errors.ErrInvalidExpiry
+		}
+	}
+
+	if err := s.checkEmailAvailable(ctx, createInvitationDTO.Email, createdAt); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.Invitation{
+		ID:        uuid.New().String(),
+		Email:     createInvitationDTO.Email,
+		Role:      role,
+		InvitedBy: invitedBy(auth.FromContext(ctx)),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repo.Create(ctx, invitation); err != nil {
+		logger.Error("[USERS-API]: Error al crear invitación", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	invitationResponse := newInvitationDTO(invitation)
+	token, err := s.sign(invitation)
+	if err == nil {
+		err = s.notifier.NotifyInvitation(ctx, invitationResponse, token)
+	}
+	if err != nil {
+		// Sin el token nadie puede aceptarla; se revoca para que se pueda volver a invitar
+		logger.Error("[USERS-API]: Error al enviar invitación", zap.String("id", invitation.ID), zap.Error(err))
+		tracing.RecordError(span, err)
+		if err := s.repo.Revoke(ctx, invitation.ID, utcNow()); err != nil {
+			logger.Error("[USERS-API]: Error al revocar invitación no enviada", zap.String("id", invitation.ID), zap.Error(err))
+		}
+		return nil, errors.ErrNotificationFailed
+	}
+
+	logger.Info("[USERS-API]: Invitación creada exitosamente", zap.String("id", invitation.ID))
+	return invitationResponse, nil
+}
+
+func (s *invitationService) ListInvitations(ctx context.Context, page dto.PageQuery) (*dto.InvitationPageDTO, error) {
+	ctx, span := tracer.Start(ctx, "InvitationService.ListInvitations")
+	defer span.End()
+
+	if err := authorize(ctx, s.logger, auth.PermUsersReadAny); err != nil {
+		return nil, err
+	}
+
+	page = page.Normalize()
+	invitations, total, err := s.repo.ReadPending(ctx, utcNow(), page.Limit, page.Offset)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener invitaciones", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	invitationResponses := make([]dto.InvitationDTO, 0, len(invitations))
+	for i := range invitations {
+		invitationResponses = append(invitationResponses, *newInvitationDTO(&invitations[i]))
+	}
+	return &dto.InvitationPageDTO{
+		Invitations: invitationResponses,
+		PageDTO:     dto.PageDTO{Total: total, Limit: page.Limit, Offset: page.Offset},
+	}, nil
+}
+
+func (s *invitationService) RevokeInvitation(ctx context.Context, id string) error {
+	ctx, span := tracer.Start(ctx, "InvitationService.RevokeInvitation")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermUsersWriteAny); err != nil {
+		return err
+	}
+
+	if err := s.repo.Revoke(ctx, id, utcNow()); err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.ErrInvitationNotFound
+		}
+		logger.Error("[USERS-API]: Error al revocar invitación", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API]: Invitación revocada", zap.String("id", id))
+	return nil
+}
+
+func (s *invitationService) AcceptInvitation(ctx context.Context, acceptInvitationDTO *dto.AcceptInvitationDTO) (*dto.UserResponseDTO, error) {
+	ctx, span := tracer.Start(ctx, "InvitationService.AcceptInvitation")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if len(s.secret) == 0 {
+		return nil, errors.ErrInvitationsDisabled
+	}
+	claims, err := s.verify(ctx, acceptInvitationDTO.Token)
+	if err != nil {
+		return nil, err
+	}
+	// La invitación se acepta desde la organización que la emitió, con su API key
+	if claims.TenantID != tenantID(ctx) {
+		logger.Warn("[USERS-API]: Invitación de otra organización", zap.String("id", claims.ID))
+		return nil, errors.ErrInvalidInvitation
+	}
+
+	acceptedAt := utcNow()
+	invitation, err := s.repo.ReadOne(ctx, claims.ID)
+	if err != nil && !stderrors.Is(err, gorm.ErrRecordNotFound) {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	if err != nil || !invitation.Pending(acceptedAt) {
+		logger.Warn("[USERS-API]: Invitación no pendiente", zap.String("id", claims.ID))
+		return nil, errors.ErrInvalidInvitation
+	}
+	if _, err := s.users.ReadByEmail(ctx, invitation.Email); err == nil {
+		return nil, errors.ErrUserExists
+	}
+
+	// Quien acepta no tiene cuenta todavía; el rol lo autorizó quien creó la invitación
+	systemCtx := auth.WithPrincipal(ctx, auth.System(invitation.TenantID))
+	user, err := s.service.CreateUser(systemCtx, &dto.CreateUserDTO{
+		Name:      acceptInvitationDTO.Name,
+		Lastname:  acceptInvitationDTO.Lastname,
+		Birthdate: acceptInvitationDTO.Birthdate,
+		Role:      invitation.Role,
+		Email:     invitation.Email,
+		Password:  acceptInvitationDTO.Password,
+		Avatar:    acceptInvitationDTO.Avatar,
+	})
+	if err != nil {
+		logger.Error("[USERS-API]: Error al crear usuario invitado", zap.String("id", invitation.ID), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	// El email único impide que la misma invitación cree dos usuarios, así que un error acá solo
+	// deja la invitación abierta hasta que venza
+	if err := s.repo.MarkAccepted(ctx, invitation.ID, acceptedAt); err != nil {
+		logger.Error("[USERS-API]: Error al marcar invitación aceptada", zap.String("id", invitation.ID), zap.Error(err))
+	}
+
+	logger.Info("[USERS-API]: Invitación aceptada", zap.String("id", invitation.ID), zap.String("user_id", user.ID))
+	return user, nil
+}
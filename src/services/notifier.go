@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/dto"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Notifier entrega al invitado el token con el que acepta la invitación. La API no envía emails,
+// cada despliegue elige cómo llegan (ver INVITATION_NOTIFIER)
+type Notifier interface {
+	NotifyInvitation(ctx context.Context, invitation *dto.InvitationDTO, token string) error
+}
+
+// logNotifier escribe el token en el log sin enmascarar, solo sirve para desarrollo local
+type logNotifier struct {
+	logger *zap.Logger
+}
+
+func NewLogNotifier(logger *zap.Logger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (n *logNotifier) NotifyInvitation(ctx context.Context, invitation *dto.InvitationDTO, token string) error {
+	log.FromContext(ctx, n.logger).Info("[USERS-API]: Invitación creada, token para aceptarla",
+		zap.String("id", invitation.ID), zap.String("email", invitation.Email), log.Reveal("token", token))
+	return nil
+}
+
+// InvitationWebhookPayload es el cuerpo que recibe INVITATION_WEBHOOK_URL por cada invitación
+type InvitationWebhookPayload struct {
+	Type       string            `json:"type"`
+	Invitation dto.InvitationDTO `json:"invitation"`
+	Token      string            `json:"token"`
+}
+
+// webhookNotifier envía cada invitación por POST a un servicio que se encarga de hacerla llegar,
+// por ejemplo el que manda los emails de la plataforma
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+	logger *zap.Logger
+}
+
+// webhookTimeout limita la espera al webhook, que se llama dentro de POST /users/invitations
+const webhookTimeout = 5 * time.Second
+
+func NewWebhookNotifier(url string, logger *zap.Logger) Notifier {
+	return &webhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+		logger: logger,
+	}
+}
+
+func (n *webhookNotifier) NotifyInvitation(ctx context.Context, invitation *dto.InvitationDTO, token string) error {
+	ctx, span := tracer.Start(ctx, "Notifier.NotifyInvitation", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+	logger := log.FromContext(ctx, n.logger)
+
+	body, err := json.Marshal(InvitationWebhookPayload{Type: "invitation", Invitation: *invitation, Token: token})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if requestID := log.RequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al enviar la invitación al webhook", zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err := fmt.Errorf("el webhook de invitaciones respondió %d", resp.StatusCode)
+		logger.Error("[USERS-API]: Error al enviar la invitación al webhook", zap.Error(err))
+		tracing.RecordError(span, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stderrors "errors"
+	"regexp"
+	"users-api/src/auth"
+	"users-api/src/cache"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/metrics"
+	"users-api/src/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var organizationIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+type OrganizationService interface {
+	// CreateOrganization crea la organización y devuelve su API key, que no se vuelve a mostrar
+	CreateOrganization(ctx context.Context, id, name string) (*dto.OrganizationDTO, string, error)
+	ListOrganizations(ctx context.Context) ([]dto.OrganizationDTO, error)
+	// RotateAPIKey reemplaza la API key de la organización y devuelve la nueva; la anterior deja de valer
+	RotateAPIKey(ctx context.Context, id string) (string, error)
+	// ResolveAPIKey devuelve la organización de una API key, o errors.ErrInvalidAPIKey si no corresponde a ninguna
+	ResolveAPIKey(ctx context.Context, apiKey string) (string, error)
+}
+
+type organizationService struct {
+	repo   client.OrganizationRepository
+	cache  cache.Cache
+	logger *zap.Logger
+}
+
+func NewOrganizationService(repo client.OrganizationRepository, cache cache.Cache, logger *zap.Logger) OrganizationService {
+	return &organizationService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+func newOrganizationDTO(organization *models.Organization) *dto.OrganizationDTO {
+	return &dto.OrganizationDTO{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		HasAPIKey: organization.APIKeyHash != nil,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+// hashAPIKey es lo que se guarda de cada API key, para que una copia de la base no permita autenticarse
+func hashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *organizationService) CreateOrganization(ctx context.Context, id, name string) (*dto.OrganizationDTO, string, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.CreateOrganization")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermOrganizationsManage); err != nil {
+		return nil, "", err
+	}
+	if !organizationIDPattern.MatchString(id) {
+		return nil, "", errors.ErrInvalidOrg
+	}
+	if name == "" {
+		name = id
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, "", err
+	}
+	apiKeyHash := hashAPIKey(apiKey)
+
+	organization := &models.Organization{ID: id, Name: name, APIKeyHash: &apiKeyHash}
+	if err := s.repo.Create(ctx, organization); err != nil {
+		if _, findErr := s.repo.ReadOne(ctx, id); findErr == nil {
+			return nil, "", errors.ErrOrgExists
+		}
+		logger.Error("[USERS-API]: Error al crear organización", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, "", err
+	}
+
+	logger.Info("[USERS-API]: Organización creada exitosamente", zap.String("id", id))
+	return newOrganizationDTO(organization), apiKey, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context) ([]dto.OrganizationDTO, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.ListOrganizations")
+	defer span.End()
+
+	if err := authorize(ctx, s.logger, auth.PermOrganizationsManage); err != nil {
+		return nil, err
+	}
+
+	organizations, err := s.repo.ReadAll(ctx)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener organizaciones", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	organizationResponses := make([]dto.OrganizationDTO, 0, len(organizations))
+	for i := range organizations {
+		organizationResponses = append(organizationResponses, *newOrganizationDTO(&organizations[i]))
+	}
+	return organizationResponses, nil
+}
+
+func (s *organizationService) RotateAPIKey(ctx context.Context, id string) (string, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.RotateAPIKey")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := authorize(ctx, s.logger, auth.PermOrganizationsManage); err != nil {
+		return "", err
+	}
+
+	organization, err := s.repo.ReadOne(ctx, id)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.ErrOrgNotFound
+		}
+		tracing.RecordError(span, err)
+		return "", err
+	}
+
+	apiKey, err := generateAPIKey()
+	if err != nil {
+		tracing.RecordError(span, err)
+		return "", err
+	}
+	if err := s.repo.UpdateAPIKeyHash(ctx, id, hashAPIKey(apiKey)); err != nil {
+		logger.Error("[USERS-API]: Error al rotar la API key", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return "", err
+	}
+	if organization.APIKeyHash != nil {
+		s.cache.Delete(ctx, cache.OrganizationKeyKey(*organization.APIKeyHash))
+	}
+
+	logger.Info("[USERS-API]: API key de la organización rotada", zap.String("id", id))
+	return apiKey, nil
+}
+
+func (s *organizationService) ResolveAPIKey(ctx context.Context, apiKey string) (string, error) {
+	ctx, span := tracer.Start(ctx, "OrganizationService.ResolveAPIKey")
+	defer span.End()
+
+	apiKeyHash := hashAPIKey(apiKey)
+	cacheKey := cache.OrganizationKeyKey(apiKeyHash)
+
+	var tenantID string
+	if err := s.cache.Get(ctx, cacheKey, &tenantID); err == nil {
+		metrics.ObserveCache("org_key", true)
+		return tenantID, nil
+	}
+	metrics.ObserveCache("org_key", false)
+
+	organization, err := s.repo.ReadByAPIKeyHash(ctx, apiKeyHash)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errors.ErrInvalidAPIKey
+		}
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al resolver la API key", zap.Error(err))
+		tracing.RecordError(span, err)
+		return "", err
+	}
+
+	s.cache.Set(ctx, cacheKey, organization.ID, cache.DefaultTTL)
+	return organization.ID, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"users-api/src/config"
+	"users-api/src/config/log"
+	"users-api/src/errors"
+	"users-api/src/models"
+	"users-api/src/utils"
+
+	"go.uber.org/zap"
+)
+
+// PasswordRules son las reglas configurables de PasswordPolicy. Los largos se cuentan en caracteres
+type PasswordRules struct {
+	MinLength int
+	MaxLength int
+	// RequiredClasses son constantes config.PasswordClass*
+	RequiredClasses []string
+}
+
+// PasswordPolicy valida las contraseñas nuevas al crear un usuario, cambiar su contraseña o resetearla
+type PasswordPolicy interface {
+	// Validate devuelve el error de la primera regla que password no cumple para user, con un código
+	// propio por regla. El chequeo de filtraciones va último porque puede consultar una fuente externa
+	Validate(ctx context.Context, password string, user *models.User) error
+}
+
+type passwordPolicy struct {
+	rules    PasswordRules
+	breached utils.BreachedPasswords
+	logger   *zap.Logger
+}
+
+// NewPasswordPolicy arma la política con rules. breached es opcional: nil deshabilita el chequeo
+func NewPasswordPolicy(rules PasswordRules, breached utils.BreachedPasswords, logger *zap.Logger) PasswordPolicy {
+	return &passwordPolicy{
+		rules:    rules,
+		breached: breached,
+		logger:   logger,
+	}
+}
+
+var passwordClasses = []struct {
+	name  string
+	match func(rune) bool
+	err   error
+}{
+	{config.PasswordClassLower, unicode.IsLower, errors.ErrPasswordNoLower},
+	{config.PasswordClassUpper, unicode.IsUpper, errors.ErrPasswordNoUpper},
+	{config.PasswordClassDigit, unicode.IsDigit, errors.ErrPasswordNoDigit},
+	{config.PasswordClassSymbol, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }, errors.ErrPasswordNoSymbol},
+}
+
+func (p *passwordPolicy) Validate(ctx context.Context, password string, user *models.User) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.rules.MinLength {
+		return errors.ErrPasswordTooShort.WithMessage(
+			fmt.Sprintf("La contraseña debe tener al menos %d caracteres", p.rules.MinLength))
+	}
+	if length > p.rules.MaxLength {
+		return errors.ErrPasswordTooLong.WithMessage(
+			fmt.Sprintf("La contraseña no puede tener más de %d caracteres", p.rules.MaxLength))
+	}
+
+	for _, class := range passwordClasses {
+		for _, required := range p.rules.RequiredClasses {
+			if required == class.name && !strings.ContainsFunc(password, class.match) {
+				return class.err
+			}
+		}
+	}
+
+	if matchesUserData(password, user) {
+		return errors.ErrPasswordUserData
+	}
+
+	if p.breached != nil {
+		breached, err := utils.IsBreached(ctx, p.breached, password)
+		if err != nil {
+			// Sin la fuente no se bloquea el alta: las demás reglas ya se cumplieron
+			log.FromContext(ctx, p.logger).Warn("[USERS-API]: No se pudo consultar la lista de contraseñas filtradas", zap.Error(err))
+			return nil
+		}
+		if breached {
+			return errors.ErrPasswordBreached
+		}
+	}
+	return nil
+}
+
+// matchesUserData compara sin distinguir mayúsculas ni espacios con el email, su parte local, el
+// nombre, el apellido y el nombre completo
+func matchesUserData(password string, user *models.User) bool {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	candidate := normalize(password)
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	for _, value := range []string{user.Email, localPart, user.Name, user.Lastname, user.Name + user.Lastname} {
+		if value := normalize(value); value != "" && value == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"users-api/src/auth"
+	"users-api/src/cache"
+	"users-api/src/client"
+	"users-api/src/config"
+	"users-api/src/errors"
+	"users-api/src/models"
+	"users-api/src/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingBreachedPasswords simula una fuente de contraseñas filtradas que no responde
+type failingBreachedPasswords struct{}
+
+func (failingBreachedPasswords) Range(context.Context, string) ([]string, error) {
+	return nil, stderrors.New("fuente no disponible")
+}
+
+func breachedList(t *testing.T, passwords ...string) utils.BreachedPasswords {
+	t.Helper()
+	var lines []string
+	for _, password := range passwords {
+		sum := sha1.Sum([]byte(password))
+		lines = append(lines, hex.EncodeToString(sum[:])+":10")
+	}
+	source, err := utils.ReadBreachedPasswords(strings.NewReader(strings.Join(lines, "\n")))
+	require.NoError(t, err)
+	return source
+}
+
+// errorCode devuelve el código de err, o vacío si no hubo error
+func errorCode(t *testing.T, err error) string {
+	t.Helper()
+	if err == nil {
+		return ""
+	}
+	var customErr *errors.Error
+	require.ErrorAs(t, err, &customErr)
+	return customErr.Code
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	user := &models.User{Email: "Ana.Perez@example.com", Name: "Ana", Lastname: "Pérez Gómez"}
+	allClasses := []string{config.PasswordClassLower, config.PasswordClassUpper, config.PasswordClassDigit, config.PasswordClassSymbol}
+
+	tests := []struct {
+		name     string
+		rules    PasswordRules
+		breached utils.BreachedPasswords
+		password string
+		code     string
+	}{
+		{"cumple las reglas por defecto", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "secreto123", ""},
+		{"más corta que el mínimo", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "corta1", "PASSWORD_TOO_SHORT"},
+		{"más larga que el máximo", PasswordRules{MinLength: 8, MaxLength: 16}, nil, strings.Repeat("a", 17), "PASSWORD_TOO_LONG"},
+		{"el largo se cuenta en caracteres", PasswordRules{MinLength: 8, MaxLength: 8}, nil, strings.Repeat("ñ", 8), ""},
+		{"sin minúscula", PasswordRules{MinLength: 8, MaxLength: 128, RequiredClasses: allClasses}, nil, "SECRETO123!", "PASSWORD_MISSING_LOWERCASE"},
+		{"sin mayúscula", PasswordRules{MinLength: 8, MaxLength: 128, RequiredClasses: allClasses}, nil, "secreto123!", "PASSWORD_MISSING_UPPERCASE"},
+		{"sin número", PasswordRules{MinLength: 8, MaxLength: 128, RequiredClasses: allClasses}, nil, "Secretooo!", "PASSWORD_MISSING_DIGIT"},
+		{"sin símbolo", PasswordRules{MinLength: 8, MaxLength: 128, RequiredClasses: allClasses}, nil, "Secreto123", "PASSWORD_MISSING_SYMBOL"},
+		{"con todas las clases", PasswordRules{MinLength: 8, MaxLength: 128, RequiredClasses: allClasses}, nil, "Secreto 123", ""},
+		{"las letras acentuadas cuentan como letras", PasswordRules{MinLength: 8, MaxLength: 128, RequiredClasses: allClasses}, nil, "Ñandú123!", ""},
+		{"igual al email", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "ana.perez@example.com", "PASSWORD_MATCHES_USER_DATA"},
+		{"igual a la parte local del email", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "ANA.PEREZ", "PASSWORD_MATCHES_USER_DATA"},
+		{"igual al apellido sin espacios", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "pérezgómez", "PASSWORD_MATCHES_USER_DATA"},
+		{"igual al nombre completo", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "Ana Pérez Gómez", "PASSWORD_MATCHES_USER_DATA"},
+		{"contiene el nombre pero no es igual", PasswordRules{MinLength: 8, MaxLength: 128}, nil, "ana-secreta-1", ""},
+		{"filtrada", PasswordRules{MinLength: 8, MaxLength: 128}, breachedList(t, "secreto123"), "secreto123", "PASSWORD_BREACHED"},
+		{"filtrada distingue mayúsculas", PasswordRules{MinLength: 8, MaxLength: 128}, breachedList(t, "secreto123"), "Secreto123", ""},
+		{"la fuente de filtradas no responde", PasswordRules{MinLength: 8, MaxLength: 128}, failingBreachedPasswords{}, "secreto123", ""},
+		{"el largo se valida antes que las filtradas", PasswordRules{MinLength: 12, MaxLength: 128}, breachedList(t, "secreto123"), "secreto123", "PASSWORD_TOO_SHORT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := NewPasswordPolicy(tt.rules, tt.breached, zap.NewNop())
+			assert.Equal(t, tt.code, errorCode(t, policy.Validate(context.Background(), tt.password, user)))
+		})
+	}
+}
+
+func TestPasswordPolicyLengthErrors(t *testing.T) {
+	policy := NewPasswordPolicy(PasswordRules{MinLength: 8, MaxLength: 16}, nil, zap.NewNop())
+
+	err := policy.Validate(context.Background(), "corta", nil)
+	assert.ErrorIs(t, err, errors.ErrPasswordTooShort)
+	assert.NotErrorIs(t, err, errors.ErrPasswordTooLong)
+	assert.ErrorContains(t, err, "al menos 8 caracteres")
+
+	err = policy.Validate(context.Background(), strings.Repeat("a", 17), nil)
+	assert.ErrorIs(t, err, errors.ErrPasswordTooLong)
+	assert.ErrorContains(t, err, "más de 16 caracteres")
+}
+
+func TestPasswordHistory(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.System(models.DefaultOrganizationID))
+	hasher, err := utils.NewBcryptHasher(4)
+	require.NoError(t, err)
+
+	newService := func(t *testing.T, history int) (*userService, client.UserRepository, string) {
+		t.Helper()
+		repo := client.NewMemoryUserRepository()
+		policy := NewPasswordPolicy(PasswordRules{MinLength: 8, MaxLength: 72}, nil, zap.NewNop())
+		service := NewUserService(repo, nil, hasher, policy, history, cache.NewNoopCache(), zap.NewNop()).(*userService)
+
+		hash, err := hasher.Hash("primera-1")
+		require.NoError(t, err)
+		user := &models.User{ID: "usuario", Email: "usuario@example.com", Name: "Usuario", Lastname: "Test", Password: hash}
+		require.NoError(t, repo.Create(ctx, user))
+		return service, repo, user.ID
+	}
+
+	// change relee el usuario antes de cada cambio, como hacen los métodos del servicio
+	change := func(t *testing.T, service *userService, repo client.UserRepository, id, password string) string {
+		t.Helper()
+		user, err := repo.ReadOne(ctx, id)
+		require.NoError(t, err)
+		return errorCode(t, service.setPassword(ctx, user, password))
+	}
+
+	tests := []struct {
+		name      string
+		history   int
+		passwords []string
+		codes     []string
+	}{
+		{"no repite la actual", 1, []string{"primera-1"}, []string{"PASSWORD_REUSED"}},
+		{"no repite las últimas", 3, []string{"segunda-2", "tercera-3", "primera-1", "segunda-2"}, []string{"", "", "PASSWORD_REUSED", "PASSWORD_REUSED"}},
+		{"las más viejas se pueden volver a usar", 2, []string{"segunda-2", "primera-1", "tercera-3", "primera-1"}, []string{"", "PASSWORD_REUSED", "", ""}},
+		{"sin historial solo se aplica la política", 0, []string{"primera-1", "corta"}, []string{"", "PASSWORD_TOO_SHORT"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, repo, id := newService(t, tt.history)
+			for i, password := range tt.passwords {
+				assert.Equal(t, tt.codes[i], change(t, service, repo, id, password), "cambio %d a %q", i, password)
+			}
+		})
+	}
+}
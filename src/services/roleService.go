@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"users-api/src/cache"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
+	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/metrics"
+	"users-api/src/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type RoleService interface {
+	ListRoles(ctx context.Context) ([]dto.RoleDTO, error)
+	// GetRole devuelve el rol con sus permisos, o errors.ErrInvalidRole si no existe
+	GetRole(ctx context.Context, name string) (*dto.RoleDTO, error)
+}
+
+type roleService struct {
+	repo   client.RoleRepository
+	cache  cache.Cache
+	logger *zap.Logger
+}
+
+func NewRoleService(repo client.RoleRepository, cache cache.Cache, logger *zap.Logger) RoleService {
+	return &roleService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+func newRoleDTO(role *models.Role) *dto.RoleDTO {
+	permissions := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		permissions = append(permissions, permission.Name)
+	}
+	return &dto.RoleDTO{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
+
+func (s *roleService) ListRoles(ctx context.Context) ([]dto.RoleDTO, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.ListRoles")
+	defer span.End()
+
+	roles, err := s.repo.ReadAll(ctx)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener roles", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	roleResponses := make([]dto.RoleDTO, 0, len(roles))
+	for i := range roles {
+		roleResponses = append(roleResponses, *newRoleDTO(&roles[i]))
+	}
+	return roleResponses, nil
+}
+
+func (s *roleService) GetRole(ctx context.Context, name string) (*dto.RoleDTO, error) {
+	ctx, span := tracer.Start(ctx, "RoleService.GetRole")
+	defer span.End()
+
+	cacheKey := cache.RoleKey(name)
+	var cachedRole dto.RoleDTO
+	if err := s.cache.Get(ctx, cacheKey, &cachedRole); err == nil {
+		metrics.ObserveCache("role", true)
+		return &cachedRole, nil
+	}
+	metrics.ObserveCache("role", false)
+
+	role, err := s.repo.ReadOne(ctx, name)
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrInvalidRole
+		}
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener rol", zap.String("role", name), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+
+	roleResponse := newRoleDTO(role)
+	s.cache.Set(ctx, cacheKey, roleResponse, cache.DefaultTTL)
+	return roleResponse, nil
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"time"
+	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/dto"
+	"users-api/src/errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const tokenIssuer = "users-api"
+
+// TokenClaims son los datos del usuario que viajan firmados en el token de acceso
+type TokenClaims struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	// TenantID es la organización del usuario. Los tokens emitidos antes de las organizaciones no
+	// lo tienen y corresponden a models.DefaultOrganizationID
+	TenantID string `json:"tid,omitempty"`
+	// Groups son los IDs de los grupos del usuario al emitir el token; un cambio de pertenencia se
+	// refleja en el próximo token
+	Groups []string `json:"groups,omitempty"`
+	// PasswordChangedAt es el último cambio de contraseña del usuario al emitir el token, en
+	// microsegundos desde epoch. Si la contraseña vuelve a cambiar, el token deja de servir
+	PasswordChangedAt int64 `json:"pwd,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type TokenService interface {
+	// Enabled indica si hay una clave configurada para emitir y verificar tokens
+	Enabled() bool
+	Issue(ctx context.Context, user *dto.UserResponseDTO) (token string, expiresAt time.Time, err error)
+	Verify(ctx context.Context, token string) (*TokenClaims, error)
+}
+
+type tokenService struct {
+	secret      []byte
+	ttl         time.Duration
+	memberships client.MembershipRepository
+	logger      *zap.Logger
+}
+
+func NewTokenService(secret string, ttl time.Duration, memberships client.MembershipRepository, logger *zap.Logger) TokenService {
+	return &tokenService{
+		secret:      []byte(secret),
+		ttl:         ttl,
+		memberships: memberships,
+		logger:      logger,
+	}
+}
+
+func (s *tokenService) Enabled() bool {
+	return len(s.secret) > 0
+}
+
+func (s *tokenService) Issue(ctx context.Context, user *dto.UserResponseDTO) (string, time.Time, error) {
+	if !s.Enabled() {
+		return "", time.Time{}, errors.ErrInvalidToken
+	}
+
+	groupIDs, err := s.memberships.ReadGroupIDs(ctx, user.ID)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al obtener los grupos para el token", zap.Error(err))
+		return "", time.Time{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.ttl)
+	claims := &TokenClaims{
+		Email:             user.Email,
+		Role:              user.Role,
+		TenantID:          user.TenantID,
+		Groups:            groupIDs,
+		PasswordChangedAt: unixMicro(user.PasswordChangedAt),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    tokenIssuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		log.FromContext(ctx, s.logger).Error("[USERS-API]: Error al firmar token", zap.Error(err))
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+func (s *tokenService) Verify(ctx context.Context, token string) (*TokenClaims, error) {
+	if !s.Enabled() {
+		return nil, errors.ErrInvalidToken
+	}
+
+	claims := &TokenClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(tokenIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		log.FromContext(ctx, s.logger).Warn("[USERS-API]: Token rechazado", zap.Error(err))
+		return nil, errors.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// unixMicro devuelve t en microsegundos desde epoch, o 0 si es nil
+func unixMicro(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixMicro()
+}
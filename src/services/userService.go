@@ -2,18 +2,28 @@ package services
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	stderrors "errors"
+	"strings"
 	"time"
+	"users-api/src/auth"
+	"users-api/src/cache"
 	"users-api/src/client"
+	"users-api/src/config/log"
+	"users-api/src/config/tracing"
 	"users-api/src/dto"
+	"users-api/src/errors"
+	"users-api/src/metrics"
 	"users-api/src/models"
+	"users-api/src/utils"
 
-	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+var tracer = otel.Tracer("users-api/src/services")
+
 type UserService interface {
 	GetAllUsers(ctx context.Context, filter map[string]interface{}) ([]dto.UserResponseDTO, error)
 	GetUserByEmail(ctx context.Context, email string) (*dto.UserResponseDTO, error)
@@ -22,171 +32,320 @@ type UserService interface {
 	CreateUser(ctx context.Context, createUserDTO *dto.CreateUserDTO) (*dto.UserResponseDTO, error)
 	UpdateUser(ctx context.Context, id string, updateUserDTO *dto.UpdateUserDTO) (*dto.UserResponseDTO, error)
 	DeleteUser(ctx context.Context, id string) error
+	// ChangeStatus activa, suspende o deshabilita una cuenta según models.CanTransitionUserStatus
+	ChangeStatus(ctx context.Context, id string, status string, reason string) (*dto.UserResponseDTO, error)
+	// ChangePassword cambia la contraseña de id. Sin auth.PermUsersPasswordReset hay que
+	// indicar la actual. Como ResetPassword, revoca los tokens emitidos antes del cambio
+	ChangePassword(ctx context.Context, id string, changePasswordDTO *dto.ChangePasswordDTO) error
+	ResetPassword(ctx context.Context, id string, newPassword string) error
 }
 
+// userService verifica los permisos del principal de ctx (ver package auth) en cada operación
 type userService struct {
-	repo        client.UserRepository
-	redisClient *redis.Client
-	logger      *zap.Logger
+	repo   client.UserRepository
+	roles  RoleService
+	hasher utils.PasswordHasher
+	policy PasswordPolicy
+	// history es la cantidad de contraseñas, incluida la actual, que no se pueden reutilizar
+	history int
+	cache   cache.Cache
+	logger  *zap.Logger
 }
 
-func NewUserService(repo client.UserRepository, redisClient *redis.Client, logger *zap.Logger) UserService {
+func NewUserService(repo client.UserRepository, roles RoleService, hasher utils.PasswordHasher, policy PasswordPolicy, history int, cache cache.Cache, logger *zap.Logger) UserService {
 	return &userService{
-		repo:        repo,
-		redisClient: redisClient,
-		logger:      logger,
+		repo:    repo,
+		roles:   roles,
+		hasher:  hasher,
+		policy:  policy,
+		history: history,
+		cache:   cache,
+		logger:  logger,
+	}
+}
+
+func newUserResponseDTO(user *models.User) *dto.UserResponseDTO {
+	return &dto.UserResponseDTO{
+		ID:                user.ID,
+		Name:              user.Name,
+		Lastname:          user.Lastname,
+		Birthdate:         user.Birthdate,
+		Role:              user.Role,
+		Email:             user.Email,
+		Avatar:            user.Avatar,
+		TenantID:          user.TenantID,
+		Status:            userStatus(user.Status),
+		StatusReason:      user.StatusReason,
+		StatusChangedAt:   user.StatusChangedAt,
+		PasswordChangedAt: user.PasswordChangedAt,
+	}
+}
+
+// userStatus devuelve el estado de la cuenta, activa si no tiene uno (ver models.User)
+func userStatus(status string) string {
+	if status == "" {
+		return models.UserStatusActive
+	}
+	return status
+}
+
+// accountStatusError devuelve el error de una cuenta que no puede iniciar sesión ni usar sus tokens, o nil si está activa
+func accountStatusError(status string) error {
+	switch userStatus(status) {
+	case models.UserStatusActive:
+		return nil
+	case models.UserStatusPending:
+		return errors.ErrAccountPending
+	case models.UserStatusSuspended:
+		return errors.ErrAccountSuspended
+	default:
+		return errors.ErrAccountDisabled
+	}
+}
+
+// userFilterFields son los campos por los que GetAllUsers puede filtrar, por igualdad
+var userFilterFields = map[string]func(*dto.UserResponseDTO) string{
+	"name":     func(user *dto.UserResponseDTO) string { return user.Name },
+	"lastname": func(user *dto.UserResponseDTO) string { return user.Lastname },
+	"role":     func(user *dto.UserResponseDTO) string { return user.Role },
+	"email":    func(user *dto.UserResponseDTO) string { return user.Email },
+	"status":   func(user *dto.UserResponseDTO) string { return user.Status },
+}
+
+// filterUsers deja los usuarios que coinciden con cada campo de filter; los campos desconocidos o
+// vacíos se ignoran. Se filtra sobre el listado cacheado para no leer la base en cada búsqueda
+func filterUsers(users []dto.UserResponseDTO, filter map[string]interface{}) []dto.UserResponseDTO {
+	filtered := []dto.UserResponseDTO{}
+	for i := range users {
+		if matchesUserFilter(&users[i], filter) {
+			filtered = append(filtered, users[i])
+		}
+	}
+	return filtered
+}
+
+func matchesUserFilter(user *dto.UserResponseDTO, filter map[string]interface{}) bool {
+	for key, field := range userFilterFields {
+		if value, ok := filter[key].(string); ok && value != "" && field(user) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// tenantID devuelve la organización del principal de ctx para armar las claves de caché. Sin
+// principal los permisos ya rechazan la operación, así que no hace falta distinguir el caso
+func tenantID(ctx context.Context) string {
+	tenantID, _ := auth.TenantFromContext(ctx)
+	return tenantID
+}
+
+// notFoundAsUserError traduce el not found del repositorio al error de aplicación, para que llegue como 404
+func notFoundAsUserError(err error) error {
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+// authorizeRoleAssignment verifica que el principal de ctx pueda asignar roles y que role exista
+func (s *userService) authorizeRoleAssignment(ctx context.Context, role string) error {
+	if err := authorize(ctx, s.logger, auth.PermRolesAssign); err != nil {
+		return err
+	}
+
+	_, err := s.roles.GetRole(ctx, role)
+	return err
+}
+
+// passwordHashError traduce el rechazo del hasher a un error de la API
+func passwordHashError(err error) error {
+	if stderrors.Is(err, utils.ErrPasswordTooLong) {
+		return errors.ErrPasswordBcryptLimit
+	}
+	return err
+}
+
+// userCacheKeys devuelve todas las claves de caché que dependen de un usuario
+func userCacheKeys(user *models.User) []string {
+	return []string{
+		cache.AllUsersKey(user.TenantID),
+		cache.UserIDKey(user.TenantID, user.ID),
+		cache.UserEmailKey(user.TenantID, user.Email),
+		cache.AuthEmailKey(user.TenantID, user.Email),
 	}
 }
 
 func (s *userService) GetAllUsers(ctx context.Context, filter map[string]interface{}) ([]dto.UserResponseDTO, error) {
-	s.logger.Info("[USERS-API]: Iniciando búsqueda de todos los usuarios")
-	cacheKey := "all_users"
+	ctx, span := tracer.Start(ctx, "UserService.GetAllUsers")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando búsqueda de todos los usuarios")
+	if err := authorize(ctx, s.logger, auth.PermUsersReadAny); err != nil {
+		return nil, err
+	}
+
 	var userResponses []dto.UserResponseDTO
 
-	if s.redisClient != nil {
-		cachedUsers, err := s.redisClient.Get(ctx, cacheKey).Result()
-		if err == nil {
-			s.logger.Info("Usuarios obtenidos desde caché")
-			err = json.Unmarshal([]byte(cachedUsers), &userResponses)
-			if err == nil {
-				return userResponses, nil
-			}
-		}
+	if err := s.cache.Get(ctx, cache.AllUsersKey(tenantID(ctx)), &userResponses); err == nil {
+		metrics.ObserveCache("all_users", true)
+		logger.Info("Usuarios obtenidos desde caché")
+		return filterUsers(userResponses, filter), nil
 	}
+	metrics.ObserveCache("all_users", false)
 
 	users, err := s.repo.ReadAll(ctx)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al obtener usuarios de BD", zap.Error(err))
+		logger.Error("[USERS-API]: Error al obtener usuarios de BD", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
-	s.logger.Info("[USERS-API]: Usuarios obtenidos exitosamente", zap.Int("count", len(users)))
+	logger.Info("[USERS-API]: Usuarios obtenidos exitosamente", zap.Int("count", len(users)))
 
 	for _, user := range users {
-		userResponses = append(userResponses, dto.UserResponseDTO{
-			ID:        user.ID,
-			Name:      user.Name,
-			Lastname:  user.Lastname,
-			Birthdate: user.Birthdate,
-			Role:      user.Role,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-		})
+		userResponses = append(userResponses, *newUserResponseDTO(&user))
 	}
 
-	if s.redisClient != nil {
-		usersJSON, _ := json.Marshal(userResponses)
-		s.redisClient.Set(ctx, cacheKey, usersJSON, 5*time.Minute)
-	}
+	s.cache.Set(ctx, cache.AllUsersKey(tenantID(ctx)), userResponses, cache.DefaultTTL)
 
-	return userResponses, nil
+	return filterUsers(userResponses, filter), nil
 }
 
 func (s *userService) GetUserByEmail(ctx context.Context, email string) (*dto.UserResponseDTO, error) {
-	s.logger.Info("[USERS-API]: Buscando usuario por email", zap.String("email", email))
-	cacheKey := fmt.Sprintf("user_email:%s", email)
-
-	cachedUser, err := s.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var userResponse dto.UserResponseDTO
-		err = json.Unmarshal([]byte(cachedUser), &userResponse)
-		if err == nil {
-			return &userResponse, nil
+	ctx, span := tracer.Start(ctx, "UserService.GetUserByEmail")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Buscando usuario por email", zap.String("email", email))
+	cacheKey := cache.UserEmailKey(tenantID(ctx), email)
+
+	var cachedUser dto.UserResponseDTO
+	if err := s.cache.Get(ctx, cacheKey, &cachedUser); err == nil {
+		metrics.ObserveCache("user_email", true)
+		if err := authorizeUser(ctx, s.logger, cachedUser.ID, auth.PermUsersReadAny, auth.PermUsersReadSelf); err != nil {
+			return nil, err
 		}
+		return &cachedUser, nil
 	}
+	metrics.ObserveCache("user_email", false)
 
 	user, err := s.repo.ReadByEmail(ctx, email)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al obtener usuario por email", zap.String("email", email), zap.Error(err))
+		logger.Error("[USERS-API]: Error al obtener usuario por email", zap.String("email", email), zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
-	s.logger.Info("[USERS-API]: Usuario encontrado por email", zap.String("id", user.ID))
-
-	userResponse := &dto.UserResponseDTO{
-		ID:        user.ID,
-		Name:      user.Name,
-		Lastname:  user.Lastname,
-		Birthdate: user.Birthdate,
-		Role:      user.Role,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
+	logger.Info("[USERS-API]: Usuario encontrado por email", zap.String("id", user.ID))
+	if err := authorizeUser(ctx, s.logger, user.ID, auth.PermUsersReadAny, auth.PermUsersReadSelf); err != nil {
+		return nil, err
 	}
 
-	userJSON, _ := json.Marshal(userResponse)
-	s.redisClient.Set(ctx, cacheKey, userJSON, 5*time.Minute)
+	userResponse := newUserResponseDTO(user)
+
+	s.cache.Set(ctx, cacheKey, userResponse, cache.DefaultTTL)
 
 	return userResponse, nil
 }
 
 func (s *userService) GetUsersList(ctx context.Context, ids []string) ([]dto.UserResponseDTO, error) {
-	s.logger.Info("[USERS-API]: Buscando lista de usuarios", zap.Strings("ids", ids))
+	ctx, span := tracer.Start(ctx, "UserService.GetUsersList")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Buscando lista de usuarios", zap.Strings("ids", ids))
+	if err := authorize(ctx, s.logger, auth.PermUsersReadAny); err != nil {
+		return nil, err
+	}
+
 	users, err := s.repo.GetUsersList(ctx, ids)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al obtener lista de usuarios", zap.Error(err))
+		logger.Error("[USERS-API]: Error al obtener lista de usuarios", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
-	s.logger.Info("[USERS-API]: Lista de usuarios obtenida exitosamente", zap.Int("count", len(users)))
+	logger.Info("[USERS-API]: Lista de usuarios obtenida exitosamente", zap.Int("count", len(users)))
 
 	var userResponses []dto.UserResponseDTO
 	for _, user := range users {
-		userResponses = append(userResponses, dto.UserResponseDTO{
-			ID:        user.ID,
-			Name:      user.Name,
-			Lastname:  user.Lastname,
-			Birthdate: user.Birthdate,
-			Role:      user.Role,
-			Email:     user.Email,
-			Avatar:    user.Avatar,
-		})
+		userResponses = append(userResponses, *newUserResponseDTO(&user))
 	}
 
 	return userResponses, nil
 }
 
 func (s *userService) GetUserByID(ctx context.Context, id string) (*dto.UserResponseDTO, error) {
-	s.logger.Info("[USERS-API]: Buscando usuario por ID", zap.String("id", id))
-	cacheKey := fmt.Sprintf("user_id:%s", id)
-
-	cachedUser, err := s.redisClient.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var userResponse dto.UserResponseDTO
-		err = json.Unmarshal([]byte(cachedUser), &userResponse)
-		if err == nil {
-			return &userResponse, nil
-		}
+	ctx, span := tracer.Start(ctx, "UserService.GetUserByID")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Buscando usuario por ID", zap.String("id", id))
+	if err := authorizeUser(ctx, s.logger, id, auth.PermUsersReadAny, auth.PermUsersReadSelf); err != nil {
+		return nil, err
+	}
+
+	cacheKey := cache.UserIDKey(tenantID(ctx), id)
+
+	var cachedUser dto.UserResponseDTO
+	if err := s.cache.Get(ctx, cacheKey, &cachedUser); err == nil {
+		metrics.ObserveCache("user_id", true)
+		return &cachedUser, nil
 	}
+	metrics.ObserveCache("user_id", false)
 
 	user, err := s.repo.ReadOne(ctx, id)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al obtener usuario por ID", zap.String("id", id), zap.Error(err))
+		logger.Error("[USERS-API]: Error al obtener usuario por ID", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
-	s.logger.Info("[USERS-API]: Usuario encontrado por ID", zap.String("id", user.ID))
+	logger.Info("[USERS-API]: Usuario encontrado por ID", zap.String("id", user.ID))
 
-	userResponse := &dto.UserResponseDTO{
-		ID:        user.ID,
-		Name:      user.Name,
-		Lastname:  user.Lastname,
-		Birthdate: user.Birthdate,
-		Role:      user.Role,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-	}
+	userResponse := newUserResponseDTO(user)
 
-	userJSON, _ := json.Marshal(userResponse)
-	s.redisClient.Set(ctx, cacheKey, userJSON, 5*time.Minute)
+	s.cache.Set(ctx, cacheKey, userResponse, cache.DefaultTTL)
 
 	return userResponse, nil
 }
 
 func (s *userService) CreateUser(ctx context.Context, createUserDTO *dto.CreateUserDTO) (*dto.UserResponseDTO, error) {
-	s.logger.Info("[USERS-API]: Iniciando creación de usuario", zap.String("email", createUserDTO.Email))
+	ctx, span := tracer.Start(ctx, "UserService.CreateUser")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
 
-	hashedPassword, err := createUserDTO.ValidateAndHash()
-	if err != nil {
-		s.logger.Error("[USERS-API]: Error al hashear contraseña", zap.Error(err))
+	logger.Info("[USERS-API]: Iniciando creación de usuario", zap.String("email", createUserDTO.Email))
+	if err := authorize(ctx, s.logger, auth.PermUsersWriteAny); err != nil {
 		return nil, err
 	}
 
+	candidate := &models.User{Name: createUserDTO.Name, Lastname: createUserDTO.Lastname, Email: createUserDTO.Email}
+	if err := s.policy.Validate(ctx, createUserDTO.Password, candidate); err != nil {
+		logger.Warn("[USERS-API]: Contraseña rechazada por la política", zap.String("email", createUserDTO.Email), zap.Error(err))
+		return nil, err
+	}
+
+	hashedPassword, err := createUserDTO.ValidateAndHash(s.hasher)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al hashear contraseña", zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, passwordHashError(err)
+	}
+
+	switch createUserDTO.Status {
+	case "":
+		createUserDTO.Status = models.UserStatusActive
+	case models.UserStatusPending, models.UserStatusActive:
+	default:
+		return nil, errors.ErrInvalidStatus
+	}
+
+	// Crear un usuario con un rol distinto del por defecto es asignarle un rol
+	if createUserDTO.Role != auth.DefaultRole {
+		if err := s.authorizeRoleAssignment(ctx, createUserDTO.Role); err != nil {
+			return nil, err
+		}
+	}
+
 	user := &models.User{
 		ID:        uuid.New().String(),
 		Name:      createUserDTO.Name,
@@ -196,41 +355,44 @@ func (s *userService) CreateUser(ctx context.Context, createUserDTO *dto.CreateU
 		Email:     createUserDTO.Email,
 		Password:  hashedPassword,
 		Avatar:    createUserDTO.Avatar,
+		Status:    createUserDTO.Status,
 	}
 
 	if err := s.repo.Create(ctx, user); err != nil {
-		s.logger.Error("[USERS-API]: Error al crear usuario en BD", zap.Error(err))
+		logger.Error("[USERS-API]: Error al crear usuario en BD", zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	s.logger.Info("[USERS-API]: Usuario creado exitosamente", zap.String("id", user.ID))
+	logger.Info("[USERS-API]: Usuario creado exitosamente", zap.String("id", user.ID))
 
-	userResponse := &dto.UserResponseDTO{
-		ID:        user.ID,
-		Name:      user.Name,
-		Lastname:  user.Lastname,
-		Birthdate: user.Birthdate,
-		Role:      user.Role,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-	}
+	userResponse := newUserResponseDTO(user)
 
-	if s.redisClient != nil {
-		s.redisClient.Del(ctx, "all_users")
-		s.redisClient.Del(ctx, fmt.Sprintf("user_email:%s", user.Email))
-		s.redisClient.Del(ctx, fmt.Sprintf("user_id:%s", user.ID))
-	}
+	s.cache.Delete(ctx, userCacheKeys(user)...)
 
 	return userResponse, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, id string, updateUserDTO *dto.UpdateUserDTO) (*dto.UserResponseDTO, error) {
-	s.logger.Info("[USERS-API]: Iniciando actualización de usuario", zap.String("id", id))
+	ctx, span := tracer.Start(ctx, "UserService.UpdateUser")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando actualización de usuario", zap.String("id", id))
+	if err := authorizeUser(ctx, s.logger, id, auth.PermUsersWriteAny, auth.PermUsersWriteSelf); err != nil {
+		return nil, err
+	}
+	if updateUserDTO.Password != nil {
+		return nil, errors.ErrPasswordInUpdate
+	}
+
 	user, err := s.repo.ReadOne(ctx, id)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al obtener usuario para actualizar", zap.String("id", id), zap.Error(err))
-		return nil, err
+		logger.Error("[USERS-API]: Error al obtener usuario para actualizar", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, notFoundAsUserError(err)
 	}
+	staleKeys := userCacheKeys(user)
 
 	if updateUserDTO.Name != nil {
 		user.Name = *updateUserDTO.Name
@@ -241,66 +403,221 @@ func (s *userService) UpdateUser(ctx context.Context, id string, updateUserDTO *
 	if updateUserDTO.Birthdate != nil {
 		user.Birthdate = *updateUserDTO.Birthdate
 	}
-	if updateUserDTO.Role != nil {
+	if updateUserDTO.Role != nil && *updateUserDTO.Role != user.Role {
+		if err := s.authorizeRoleAssignment(ctx, *updateUserDTO.Role); err != nil {
+			return nil, err
+		}
 		user.Role = *updateUserDTO.Role
 	}
 	if updateUserDTO.Email != nil {
 		user.Email = *updateUserDTO.Email
 	}
-	if updateUserDTO.Password != nil {
-		user.Password = *updateUserDTO.Password
-	}
 	if updateUserDTO.Avatar != nil {
 		user.Avatar = *updateUserDTO.Avatar
 	}
 
 	if err := s.repo.Update(ctx, id, user); err != nil {
-		s.logger.Error("[USERS-API]: Error al actualizar usuario", zap.String("id", id), zap.Error(err))
+		logger.Error("[USERS-API]: Error al actualizar usuario", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return nil, err
 	}
 
-	s.logger.Info("[USERS-API]: Usuario actualizado exitosamente", zap.String("id", id))
+	logger.Info("[USERS-API]: Usuario actualizado exitosamente", zap.String("id", id))
 
-	userResponse := &dto.UserResponseDTO{
-		ID:        user.ID,
-		Name:      user.Name,
-		Lastname:  user.Lastname,
-		Birthdate: user.Birthdate,
-		Role:      user.Role,
-		Email:     user.Email,
-		Avatar:    user.Avatar,
-	}
+	userResponse := newUserResponseDTO(user)
 
-	if s.redisClient != nil {
-		s.redisClient.Del(ctx, "all_users")
-		s.redisClient.Del(ctx, fmt.Sprintf("user_email:%s", user.Email))
-		s.redisClient.Del(ctx, fmt.Sprintf("user_id:%s", user.ID))
-	}
+	// Si cambió el email también hay que invalidar las claves del email anterior
+	s.cache.Delete(ctx, append(staleKeys, userCacheKeys(user)...)...)
 
 	return userResponse, nil
 }
 
 func (s *userService) DeleteUser(ctx context.Context, id string) error {
-	s.logger.Info("[USERS-API]: Iniciando eliminación de usuario", zap.String("id", id))
+	ctx, span := tracer.Start(ctx, "UserService.DeleteUser")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando eliminación de usuario", zap.String("id", id))
+	if err := authorizeUser(ctx, s.logger, id, auth.PermUsersDeleteAny, auth.PermUsersWriteSelf); err != nil {
+		return err
+	}
+
 	user, err := s.repo.ReadOne(ctx, id)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al obtener usuario para eliminar", zap.String("id", id), zap.Error(err))
-		return err
+		logger.Error("[USERS-API]: Error al obtener usuario para eliminar", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return notFoundAsUserError(err)
 	}
 
 	err = s.repo.Delete(ctx, id)
 	if err != nil {
-		s.logger.Error("[USERS-API]: Error al eliminar usuario", zap.String("id", id), zap.Error(err))
+		logger.Error("[USERS-API]: Error al eliminar usuario", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
 		return err
 	}
 
-	s.logger.Info("[USERS-API]: Usuario eliminado exitosamente", zap.String("id", id))
+	logger.Info("[USERS-API]: Usuario eliminado exitosamente", zap.String("id", id))
+
+	s.cache.Delete(ctx, userCacheKeys(user)...)
+
+	return nil
+}
+
+func (s *userService) ChangeStatus(ctx context.Context, id string, status string, reason string) (*dto.UserResponseDTO, error) {
+	ctx, span := tracer.Start(ctx, "UserService.ChangeStatus")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando cambio de estado de usuario", zap.String("id", id), zap.String("status", status))
+	if err := authorize(ctx, s.logger, auth.PermUsersStatus); err != nil {
+		return nil, err
+	}
+	if !models.IsUserStatus(status) || strings.TrimSpace(reason) == "" {
+		return nil, errors.ErrInvalidStatus
+	}
+
+	user, err := s.repo.ReadOne(ctx, id)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al obtener usuario para cambiar su estado", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, notFoundAsUserError(err)
+	}
+
+	from := userStatus(user.Status)
+	if !models.CanTransitionUserStatus(from, status) {
+		logger.Warn("[USERS-API]: Cambio de estado no permitido", zap.String("id", id),
+			zap.String("from", from), zap.String("to", status))
+		return nil, errors.ErrStatusTransition
+	}
+
+	changedAt := time.Now().UTC()
+	if err := s.repo.UpdateStatus(ctx, id, from, status, reason, changedAt); err != nil {
+		// Otro cambio se adelantó desde que se leyó el usuario
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.ErrStatusTransition
+		}
+		logger.Error("[USERS-API]: Error al cambiar el estado del usuario", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	user.Status = status
+	user.StatusReason = reason
+	user.StatusChangedAt = &changedAt
+
+	logger.Info("[USERS-API]: Estado de usuario actualizado", zap.String("id", id),
+		zap.String("from", from), zap.String("to", status))
 
-	if s.redisClient != nil {
-		s.redisClient.Del(ctx, "all_users")
-		s.redisClient.Del(ctx, fmt.Sprintf("user_email:%s", user.Email))
-		s.redisClient.Del(ctx, fmt.Sprintf("user_id:%s", user.ID))
+	// El middleware de tokens lee el estado de la caché por ID, así el cambio aplica en la próxima solicitud
+	s.cache.Delete(ctx, userCacheKeys(user)...)
+
+	return newUserResponseDTO(user), nil
+}
+
+func (s *userService) ChangePassword(ctx context.Context, id string, changePasswordDTO *dto.ChangePasswordDTO) error {
+	ctx, span := tracer.Start(ctx, "UserService.ChangePassword")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando cambio de contraseña", zap.String("id", id))
+	if err := authorizeUser(ctx, s.logger, id, auth.PermUsersWriteAny, auth.PermUsersWriteSelf); err != nil {
+		return err
+	}
+
+	user, err := s.repo.ReadOne(ctx, id)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al obtener usuario para cambiar su contraseña", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return notFoundAsUserError(err)
+	}
+
+	// Si se indica la contraseña actual se verifica siempre, también para quien no la necesita
+	if changePasswordDTO.CurrentPassword != "" || !auth.FromContext(ctx).Can(auth.PermUsersPasswordReset) {
+		if changePasswordDTO.CurrentPassword == "" {
+			return errors.ErrCurrentPassword
+		}
+		if ok, _ := s.hasher.Verify(changePasswordDTO.CurrentPassword, user.Password); !ok {
+			logger.Warn("[USERS-API]: Contraseña actual incorrecta", zap.String("id", id))
+			return errors.ErrWrongPassword
+		}
+	}
+
+	if err := s.setPassword(ctx, user, changePasswordDTO.NewPassword); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API]: Contraseña cambiada exitosamente", zap.String("id", id))
+	return nil
+}
+
+// ResetPassword reemplaza la contraseña de un usuario sin pedir la actual, solo para uso administrativo
+func (s *userService) ResetPassword(ctx context.Context, id string, newPassword string) error {
+	ctx, span := tracer.Start(ctx, "UserService.ResetPassword")
+	defer span.End()
+	logger := log.FromContext(ctx, s.logger)
+
+	logger.Info("[USERS-API]: Iniciando reseteo de contraseña", zap.String("id", id))
+	if err := authorize(ctx, s.logger, auth.PermUsersPasswordReset); err != nil {
+		return err
+	}
+
+	user, err := s.repo.ReadOne(ctx, id)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al obtener usuario para resetear contraseña", zap.String("id", id), zap.Error(err))
+		tracing.RecordError(span, err)
+		return notFoundAsUserError(err)
+	}
+
+	if err := s.setPassword(ctx, user, newPassword); err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+
+	logger.Info("[USERS-API]: Contraseña reseteada exitosamente", zap.String("id", id))
+	return nil
+}
+
+// setPassword valida password con la política y el historial, la guarda hasheada y revoca los tokens
+// del usuario: los emitidos antes de PasswordChangedAt se rechazan en CredentialService
+func (s *userService) setPassword(ctx context.Context, user *models.User, password string) error {
+	logger := log.FromContext(ctx, s.logger)
+
+	if err := s.policy.Validate(ctx, password, user); err != nil {
+		logger.Warn("[USERS-API]: Contraseña rechazada por la política", zap.String("id", user.ID), zap.Error(err))
+		return err
+	}
+
+	if s.history > 0 {
+		previous, err := s.repo.ReadPasswordHistory(ctx, user.ID, s.history-1)
+		if err != nil {
+			logger.Error("[USERS-API]: Error al obtener el historial de contraseñas", zap.String("id", user.ID), zap.Error(err))
+			return err
+		}
+		for _, hash := range append([]string{user.Password}, previous...) {
+			if ok, _ := s.hasher.Verify(password, hash); ok {
+				logger.Warn("[USERS-API]: Contraseña reutilizada", zap.String("id", user.ID))
+				return errors.ErrPasswordReused
+			}
+		}
+	}
+
+	hashedPassword, err := s.hasher.Hash(password)
+	if err != nil {
+		logger.Error("[USERS-API]: Error al hashear contraseña", zap.Error(err))
+		return passwordHashError(err)
+	}
+
+	// Los tokens guardan la fecha en microsegundos, la precisión de PostgreSQL
+	changedAt := time.Now().UTC().Truncate(time.Microsecond)
+	if err := s.repo.ChangePassword(ctx, user.ID, user.Password, hashedPassword, changedAt, max(s.history-1, 0)); err != nil {
+		// Otro cambio se adelantó desde que se leyó el usuario
+		if stderrors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.ErrPasswordChanged
+		}
+		logger.Error("[USERS-API]: Error al actualizar contraseña", zap.String("id", user.ID), zap.Error(err))
+		return err
 	}
 
+	s.cache.Delete(ctx, userCacheKeys(user)...)
 	return nil
 }
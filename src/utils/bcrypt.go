@@ -1,18 +1,59 @@
 package utils
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+// BcryptMaxPasswordBytes es el largo que bcrypt usa de la contraseña; ignora el resto
+const BcryptMaxPasswordBytes = 72
+
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher devuelve un PasswordHasher bcrypt con el costo indicado, entre bcrypt.MinCost y
+// bcrypt.MaxCost. Los hashes mantienen el formato $2a$ de siempre para seguir siendo compatibles
+func NewBcryptHasher(cost int) (PasswordHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("costo de bcrypt inválido %d, debe estar entre %d y %d", cost, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &bcryptHasher{cost: cost}, nil
+}
+
+// Hash devuelve ErrPasswordTooLong con más de 72 bytes en lugar de hashear solo el principio
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	if len(password) > BcryptMaxPasswordBytes {
+		return "", ErrPasswordTooLong
+	}
+	defer observeHash(time.Now())
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	if err != nil {
 		return "", err
 	}
 	return string(hash), nil
 }
 
-func CheckPasswordHash(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
+func (h *bcryptHasher) Verify(password, hash string) (bool, bool) {
+	if !verifyPassword(password, hash) {
+		return false, false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return true, !isBcryptHash(hash) || err != nil || cost != h.cost
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// verifyBcrypt rechaza las contraseñas de más de 72 bytes: bcrypt las truncaría y aceptaría
+// cualquier contraseña que empiece igual
+func verifyBcrypt(password, hash string) bool {
+	if len(password) > BcryptMaxPasswordBytes {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
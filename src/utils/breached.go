@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// breachedPrefixLength es el largo del prefijo de SHA-1 con el que se consulta un rango, como en la
+// API de rangos de Have I Been Pwned
+const breachedPrefixLength = 5
+
+//go:embed breached_passwords.txt
+var bundledBreachedPasswords []byte
+
+// BreachedPasswords es una fuente de contraseñas filtradas consultada con k-anonimato: Range recibe
+// solo los primeros 5 caracteres hexadecimales del SHA-1 de la contraseña y devuelve los 35 restantes
+// de cada hash filtrado con ese prefijo, en mayúsculas. Así una fuente remota nunca ve la contraseña
+// ni su hash completo
+type BreachedPasswords interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// IsBreached indica si password está en source
+func IsBreached(ctx context.Context, source BreachedPasswords, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := source.Range(ctx, hash[:breachedPrefixLength])
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(suffixes, hash[breachedPrefixLength:]), nil
+}
+
+// breachedPasswordFile guarda en memoria los sufijos de cada prefijo, ordenados
+type breachedPasswordFile struct {
+	ranges map[string][]string
+}
+
+// NewBundledBreachedPasswords devuelve la lista de contraseñas comunes incluida en el binario
+func NewBundledBreachedPasswords() (BreachedPasswords, error) {
+	return ReadBreachedPasswords(bytes.NewReader(bundledBreachedPasswords))
+}
+
+// LoadBreachedPasswords lee un archivo con un SHA-1 por línea, opcionalmente seguido de :VECES como en
+// las descargas de Have I Been Pwned. Se carga completo en memoria, así que conviene usar un subconjunto
+func LoadBreachedPasswords(path string) (BreachedPasswords, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadBreachedPasswords(file)
+}
+
+// ReadBreachedPasswords lee el formato de LoadBreachedPasswords. Ignora las líneas vacías y las que
+// empiezan con #
+func ReadBreachedPasswords(r io.Reader) (BreachedPasswords, error) {
+	ranges := map[string][]string{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		hash, _, _ := strings.Cut(text, ":")
+		hash = strings.ToUpper(hash)
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("línea %d: se esperaba un SHA-1 en hexadecimal", line)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("línea %d: %w", line, err)
+		}
+		prefix := hash[:breachedPrefixLength]
+		ranges[prefix] = append(ranges[prefix], hash[breachedPrefixLength:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for prefix := range ranges {
+		slices.Sort(ranges[prefix])
+		ranges[prefix] = slices.Compact(ranges[prefix])
+	}
+	return &breachedPasswordFile{ranges: ranges}, nil
+}
+
+func (f *breachedPasswordFile) Range(_ context.Context, prefix string) ([]string, error) {
+	return f.ranges[strings.ToUpper(prefix)], nil
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SHA-1 de "password" y de "123456", que comparten el formato de las descargas de Have I Been Pwned
+const (
+	passwordSHA1 = "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"
+	numbersSHA1  = "7C4A8D09CA3762AF61E59520943DC26494F8941B"
+)
+
+func TestReadBreachedPasswords(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		ranges map[string][]string
+		err    string
+	}{
+		{
+			name:   "hashes con y sin conteo",
+			input:  passwordSHA1 + ":3861493\n" + numbersSHA1 + "\n",
+			ranges: map[string][]string{"5BAA6": {passwordSHA1[5:]}, "7C4A8": {numbersSHA1[5:]}},
+		},
+		{
+			name:   "ignora líneas vacías, comentarios y espacios",
+			input:  "# lista de prueba\n\n  " + passwordSHA1 + "  \r\n",
+			ranges: map[string][]string{"5BAA6": {passwordSHA1[5:]}},
+		},
+		{
+			name:   "minúsculas y duplicados",
+			input:  strings.ToLower(passwordSHA1) + "\n" + passwordSHA1 + ":2\n",
+			ranges: map[string][]string{"5BAA6": {passwordSHA1[5:]}},
+		},
+		{
+			name:   "ordena los sufijos del mismo prefijo",
+			input:  "5BAA6FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF\n" + passwordSHA1,
+			ranges: map[string][]string{"5BAA6": {passwordSHA1[5:], "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF"}},
+		},
+		{name: "vacío", input: "", ranges: map[string][]string{}},
+		{name: "hash corto", input: passwordSHA1 + "\n" + passwordSHA1[:39], err: "línea 2"},
+		{name: "hash largo", input: passwordSHA1 + "0", err: "línea 1"},
+		{name: "no hexadecimal", input: "# comentario\nZBAA61E4C9B93F3F0682250B6CF8331B7EE68FD8", err: "línea 2"},
+		{name: "conteo sin hash", input: ":12", err: "línea 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := ReadBreachedPasswords(strings.NewReader(tt.input))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.ranges, source.(*breachedPasswordFile).ranges)
+		})
+	}
+}
+
+func TestIsBreached(t *testing.T) {
+	source, err := ReadBreachedPasswords(strings.NewReader(passwordSHA1 + ":3861493"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		password string
+		breached bool
+	}{
+		{"password", true},
+		{"Password", false},
+		{"123456", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			breached, err := IsBreached(context.Background(), source, tt.password)
+			require.NoError(t, err)
+			assert.Equal(t, tt.breached, breached)
+		})
+	}
+
+	t.Run("el rango se consulta con el prefijo en minúsculas o mayúsculas", func(t *testing.T) {
+		suffixes, err := source.Range(context.Background(), "5baa6")
+		require.NoError(t, err)
+		assert.Equal(t, []string{passwordSHA1[5:]}, suffixes)
+	})
+}
+
+func TestBreachedPasswordSources(t *testing.T) {
+	t.Run("la lista incluida se puede leer", func(t *testing.T) {
+		source, err := NewBundledBreachedPasswords()
+		require.NoError(t, err)
+		breached, err := IsBreached(context.Background(), source, "password")
+		require.NoError(t, err)
+		assert.True(t, breached)
+	})
+
+	t.Run("archivo", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "pwned.txt")
+		require.NoError(t, os.WriteFile(path, []byte(numbersSHA1+":37359195\n"), 0o600))
+		source, err := LoadBreachedPasswords(path)
+		require.NoError(t, err)
+		breached, err := IsBreached(context.Background(), source, "123456")
+		require.NoError(t, err)
+		assert.True(t, breached)
+
+		_, err = LoadBreachedPasswords(filepath.Join(t.TempDir(), "no-existe.txt"))
+		assert.Error(t, err)
+	})
+}
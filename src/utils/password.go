@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"users-api/src/metrics"
+)
+
+// ErrPasswordTooLong indica una contraseña que el algoritmo no puede hashear completa
+var ErrPasswordTooLong = errors.New("la contraseña supera el largo máximo del algoritmo de hash")
+
+// PasswordHasher hashea contraseñas con un algoritmo y parámetros fijos. Verify acepta también los
+// hashes de los demás algoritmos soportados, para que cambiar de algoritmo no invalide las contraseñas
+// existentes: needsRehash indica que el hash es correcto pero conviene reemplazarlo por Hash(password)
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, hash string) (ok bool, needsRehash bool)
+}
+
+// verifyPassword compara password con un hash de cualquier algoritmo soportado, según su prefijo
+func verifyPassword(password, hash string) bool {
+	start := time.Now()
+	defer func() {
+		metrics.PasswordHashDuration.WithLabelValues("compare").Observe(time.Since(start).Seconds())
+	}()
+
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		return verifyArgon2id(password, hash)
+	case isBcryptHash(hash):
+		return verifyBcrypt(password, hash)
+	default:
+		return false
+	}
+}
+
+func observeHash(start time.Time) {
+	metrics.PasswordHashDuration.WithLabelValues("hash").Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,8 @@
+//go:build tools
+
+// Dependencias de herramientas de generación, para fijar su versión en go.mod
+package main
+
+import (
+	_ "github.com/99designs/gqlgen"
+)